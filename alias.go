@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/duanqy/cli/internal/argfile"
+)
+
+/*
+Aliases sets the source of the user defined aliases of the top level commands, e.g. AliasFile(path), which maps
+an alias name to the args it stands for, like git config alias.co "checkout -b":
+
+	co = checkout -b
+
+The args are split like in a shell, and the ones given after the alias are appended to them. An alias can expand to
+another alias, but not to itself, and the aliases named after a command are ignored. An alias starting with ! runs
+a shell command instead, if enabled with ShellAliases.
+
+The source is read once, the first time an alias is needed, and the aliases are listed in the help of the app. If it
+cannot be read, a warning is printed and the app runs without aliases.
+*/
+func (a *App) Aliases(load func() (map[string]string, error)) {
+	a.loadAliases = load
+}
+
+// ShellAliases enables the aliases starting with !, which run the rest of the alias with sh -c, with the args
+// given after the alias
+func (a *App) ShellAliases(enabled bool) {
+	a.shellAliases = enabled
+}
+
+/*
+AliasFile reads aliases from a file with one alias per line, e.g.:
+
+	# my aliases
+	co = checkout -b
+	ll = !ls -l
+
+A missing file defines no alias. The path is used as is, without expanding ~:
+
+	home, _ := os.UserHomeDir()
+	app.Aliases(cli.AliasFile(filepath.Join(home, ".apprc")))
+*/
+func AliasFile(path string) func() (map[string]string, error) {
+	return func() (map[string]string, error) {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		res := map[string]string{}
+		scanner := bufio.NewScanner(f)
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+			kv := strings.SplitN(text, "=", 2)
+			name := strings.TrimSpace(kv[0])
+			if len(kv) != 2 || name == "" || strings.ContainsAny(name, " \t") {
+				return nil, fmt.Errorf("%s:%d: invalid alias definition, was expecting name = args", path, line)
+			}
+			res[name] = strings.TrimSpace(kv[1])
+		}
+		return res, scanner.Err()
+	}
+}
+
+// userAliases returns the aliases of the app which do not shadow a command, loading them if needed. The aliases
+// which cannot be loaded are ignored with a warning, so that a broken alias file does not make the app unusable
+func (c *Cmd) userAliases() map[string]string {
+	root := c.root()
+	if root.loadAliases == nil || root.aliasesLoaded {
+		return root.aliasDefs
+	}
+	root.aliasesLoaded = true
+
+	defs, err := root.loadAliases()
+	if err != nil {
+		_, _ = fmt.Fprintf(stdErr, "warning: ignoring the aliases: %v\n", err)
+		return nil
+	}
+	root.aliasDefs = map[string]string{}
+	for name, def := range defs {
+		if root.subcommand(name) == nil {
+			root.aliasDefs[name] = def
+		}
+	}
+	return root.aliasDefs
+}
+
+/*
+expandAliases replaces the first alias found where c expects a command name by its args. It returns true if the alias
+was a shell alias, which was run with the rest of the args.
+*/
+func (c *Cmd) expandAliases(args []string) ([]string, bool, error) {
+	defs := c.userAliases()
+	if len(defs) == 0 {
+		return args, false, nil
+	}
+
+	for i, arg := range args {
+		if arg == "--" || c.subcommand(arg) != nil {
+			break
+		}
+		if _, found := defs[arg]; !found {
+			continue
+		}
+		if _, ok := c.fsm.Match(args[:i]); !ok {
+			continue
+		}
+
+		words, chain := []string{arg}, []string(nil)
+		for {
+			name := words[0]
+			def, isAlias := defs[name]
+			if !isAlias {
+				break
+			}
+			for _, seen := range chain {
+				if seen == name {
+					return nil, false, fmt.Errorf("alias loop: %s -> %s", strings.Join(chain, " -> "), name)
+				}
+			}
+			chain = append(chain, name)
+
+			if strings.HasPrefix(def, "!") {
+				if !c.root().shellAliases {
+					return nil, false, fmt.Errorf("alias %s runs a shell command, which is disabled", name)
+				}
+				return nil, true, c.runShellAlias(name, def[1:], append(words[1:], args[i+1:]...))
+			}
+			fields, err := argfile.Fields(def)
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid alias %s: %v", name, err)
+			}
+			if len(fields) == 0 {
+				return nil, false, fmt.Errorf("alias %s is empty", name)
+			}
+			words = append(fields, words[1:]...)
+		}
+
+		res := append(append(append([]string{}, args[:i]...), words...), args[i+1:]...)
+		return res, false, nil
+	}
+	return args, false, nil
+}
+
+// runShellAlias runs the shell command of the alias name with args
+func (c *Cmd) runShellAlias(name, command string, args []string) error {
+	cmd := exec.Command("sh", append([]string{"-c", command + ` "$@"`, name}, args...)...)
+	cmd.Env = append(os.Environ(), c.persistentEnv()...)
+	return c.runProcess(cmd)
+}
+
+// aliasNames returns the sorted names of the aliases of the app
+func (c *Cmd) aliasNames() []string {
+	defs := c.userAliases()
+	var res []string
+	for name := range defs {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
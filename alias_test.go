@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliases(t *testing.T) {
+	var (
+		ran    []string
+		newApp = func(aliases map[string]string) (*App, func() string) {
+			app, out := testApp(t, "app")
+			app.Aliases(func() (map[string]string, error) {
+				return aliases, nil
+			})
+			app.Command("checkout", "", func(cmd *Cmd) {
+				cmd.Spec = "[-b] BRANCH"
+				create := cmd.Option("b", "").Bool(false)
+				branch := cmd.Argument("BRANCH", "").String("")
+				cmd.Action = func(ctx Context) error {
+					ran = []string{"checkout", *branch}
+					if *create {
+						ran = append(ran, "-b")
+					}
+					return nil
+				}
+			})
+			return app, out.String
+		}
+		aliases = map[string]string{
+			"co":       "checkout -b",
+			"feat":     "co 'feature x'",
+			"loop":     "loop2",
+			"loop2":    "loop",
+			"ll":       "!ls -l",
+			"checkout": "co",
+		}
+	)
+
+	cases := []struct {
+		args []string
+		ran  []string
+		err  string
+	}{
+		{args: []string{"co", "main"}, ran: []string{"checkout", "main", "-b"}},
+		{args: []string{"feat"}, ran: []string{"checkout", "feature x", "-b"}},
+		{args: []string{"checkout", "main"}, ran: []string{"checkout", "main"}},
+		{args: []string{"loop"}, err: "alias loop: loop -> loop2 -> loop"},
+		{args: []string{"ll"}, err: "alias ll runs a shell command, which is disabled"},
+	}
+	for _, cas := range cases {
+		t.Run(cas.args[0], func(t *testing.T) {
+			ran = nil
+			app, out := newApp(aliases)
+			err := app.Run(append([]string{"app"}, cas.args...))
+			if cas.err != "" {
+				require.EqualError(t, err, cas.err)
+				require.Contains(t, out(), "error: "+cas.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, cas.ran, ran)
+		})
+	}
+
+	t.Run("help", func(t *testing.T) {
+		app, out := newApp(aliases)
+		require.NoError(t, app.Run([]string{"app", "-h"}))
+		require.Regexp(t, `\nAliases: +\n  co +checkout -b\n`, out())
+		require.NotRegexp(t, `\n  checkout +co\n`, out())
+	})
+
+	t.Run("shell", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("shell aliases run sh")
+		}
+		var stdout bytes.Buffer
+		prev := stdOut
+		stdOut = &stdout
+		t.Cleanup(func() {
+			stdOut = prev
+		})
+
+		app, _ := newApp(map[string]string{"hi": "!echo hi"})
+		app.ShellAliases(true)
+
+		require.NoError(t, app.Run([]string{"app", "hi", "there"}))
+		require.Equal(t, "hi there\n", stdout.String())
+	})
+
+	t.Run("load error", func(t *testing.T) {
+		app, out := testApp(t, "app")
+		app.Aliases(func() (map[string]string, error) {
+			return nil, errors.New("boom")
+		})
+		app.Command("checkout", "", nil)
+
+		require.NoError(t, app.Run([]string{"app", "--help"}))
+		require.Contains(t, out.String(), "warning: ignoring the aliases: boom\n")
+		require.Contains(t, out.String(), "Usage: app COMMAND [arg...]")
+	})
+}
+
+func TestAliasFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "aliases")
+	require.NoError(t, os.WriteFile(path, []byte("# comment\n\nco = checkout -b\nll=!ls -l\n"), 0644))
+
+	aliases, err := AliasFile(path)()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"co": "checkout -b", "ll": "!ls -l"}, aliases)
+
+	aliases, err = AliasFile(filepath.Join(dir, "missing"))()
+	require.NoError(t, err)
+	require.Empty(t, aliases)
+
+	require.NoError(t, os.WriteFile(path, []byte("co = checkout\nbroken\n"), 0644))
+	_, err = AliasFile(path)()
+	require.EqualError(t, err, path+":2: invalid alias definition, was expecting name = args")
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"github.com/duanqy/cli/internal/argfile"
+)
+
+/*
+ResponseFiles enables the expansion of the @file args into the args read from the file before parsing, e.g. to get
+around the limits on the length of the command line:
+
+	app build @paths.txt
+
+The args are separated by white spaces, and can be quoted like in a shell. A # at the start of an arg starts a
+comment, a backslash at the end of a line continues it, @@ escapes a literal at-sign and response files can include
+other response files, relative to their own directory, up to 10 levels deep. Nothing is expanded after the --
+operator.
+*/
+func (a *App) ResponseFiles(enabled bool) {
+	a.responseFiles = enabled
+}
+
+// ArgSource tells where an arg comes from: a response file and a line, or the command line if File is empty
+type ArgSource struct {
+	Arg  string
+	File string
+	Line int
+}
+
+// ArgSources returns the args of the last call to Run after the expansion of the response files, with their source.
+// It returns nil unless ResponseFiles is enabled.
+func (a *App) ArgSources() []ArgSource {
+	return a.sources
+}
+
+func (a *App) expandArgs(args []string) ([]string, error) {
+	a.sources = nil
+	if !a.responseFiles {
+		return args, nil
+	}
+	expanded, sources, err := argfile.Expand(args)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range sources {
+		a.sources = append(a.sources, ArgSource{Arg: src.Arg, File: src.File, Line: src.Line})
+	}
+	return expanded, nil
+}
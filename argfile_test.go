@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+	paths := filepath.Join(dir, "paths.txt")
+	require.NoError(t, os.WriteFile(paths, []byte("# generated by the build\n-v src/main.go\n'src/with space.go' \\\n  @@tag\n"), 0644))
+
+	app, out := testApp(t, "app")
+	app.ResponseFiles(true)
+	var (
+		verbose bool
+		files   []string
+	)
+	app.Command("build", "", func(cmd *Cmd) {
+		cmd.Spec = "[-v] FILE..."
+		cmd.Option("v verbose", "").BoolVar(&verbose, false)
+		cmd.Argument("FILE", "").StringSliceVar(&files, nil)
+		cmd.Action = func(ctx Context) error {
+			return nil
+		}
+	})
+
+	require.NoError(t, app.Run([]string{"app", "build", "@" + paths, "--", "@last"}))
+	require.True(t, verbose)
+	require.Equal(t, []string{"src/main.go", "src/with space.go", "@tag", "@last"}, files)
+	require.Equal(t, []ArgSource{
+		{Arg: "build"},
+		{Arg: "-v", File: paths, Line: 2},
+		{Arg: "src/main.go", File: paths, Line: 2},
+		{Arg: "src/with space.go", File: paths, Line: 3},
+		{Arg: "@tag", File: paths, Line: 4},
+		{Arg: "--"},
+		{Arg: "@last"},
+	}, app.ArgSources())
+
+	require.Error(t, app.Run([]string{"app", "build", "@" + filepath.Join(dir, "none.txt")}))
+	require.Contains(t, out.String(), "error: cannot read response file")
+	require.Nil(t, app.ArgSources())
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+)
+
+// Category puts the command in a section of the help of its parent, e.g. "Cluster management", instead of the
+// Commands section
+func Category(name string) CommandOption {
+	return func(c *Cmd) {
+		c.category = name
+	}
+}
+
+// SortCommands lists the commands of every section of the help in alphabetical order instead of declaration order
+func (a *App) SortCommands(enabled bool) {
+	a.sortCommands = enabled
+}
+
+// commandGroup is a section of the commands in the help
+type commandGroup struct {
+	category string
+	commands []*Cmd
+}
+
+func (g commandGroup) title() string {
+	if g.category == "" {
+		return "Commands"
+	}
+	return g.category
+}
+
+func (c *Cmd) hasCategory(category string) bool {
+	for _, sub := range c.commands {
+		if sub.category == category {
+			return true
+		}
+	}
+	return false
+}
+
+// commandGroups returns the sections of the visible sub commands of c: the commands without a category first, then
+// the categories listed in c.Categories, then the other categories in declaration order
+func (c *Cmd) commandGroups() []commandGroup {
+	var (
+		groups []commandGroup
+		index  = map[string]int{}
+		add    = func(category string) {
+			if _, ok := index[category]; !ok {
+				index[category] = len(groups)
+				groups = append(groups, commandGroup{category: category})
+			}
+		}
+	)
+	add("")
+	for _, category := range c.Categories {
+		add(category)
+	}
+	for _, sub := range c.visibleCommands() {
+		add(sub.category)
+		i := index[sub.category]
+		groups[i].commands = append(groups[i].commands, sub)
+	}
+
+	var res []commandGroup
+	for _, g := range groups {
+		if len(g.commands) == 0 {
+			continue
+		}
+		if c.root().sortCommands {
+			sort.SliceStable(g.commands, func(i, j int) bool {
+				return g.commands[i].name < g.commands[j].name
+			})
+		}
+		res = append(res, g)
+	}
+	return res
+}
+
+// formatCommandNamesForHelp returns the name of the command followed by its other aliases, e.g. "run, r"
+func formatCommandNamesForHelp(c *Cmd) string {
+	return strings.Join(c.aliases, ", ")
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandCategories(t *testing.T) {
+	newApp := func() (*App, func() string) {
+		app, out := testApp(t, "app")
+		app.Categories = []string{"Debugging"}
+		app.Command("version", "Print the version", nil)
+		app.Command("scale s", "Scale the app", nil, Category("Cluster management"))
+		app.Command("logs l", "Print the logs", nil, Category("Debugging"))
+		app.Command("drain", "Drain a node", nil, Category("Cluster management"))
+		app.Command("trace", "Trace the calls", nil, Category("Debugging"), Hidden())
+		app.Command("attach a", "Attach to the app", nil, Category("Debugging"))
+		app.Command("completion", "Print the completion script", nil)
+		return app, out.String
+	}
+
+	t.Run("declaration order", func(t *testing.T) {
+		app, out := newApp()
+		require.NoError(t, app.Run([]string{"app", "-h"}))
+		requireGolden(t, "categories-help-output.txt", out())
+	})
+
+	t.Run("sorted", func(t *testing.T) {
+		app, out := newApp()
+		app.SortCommands(true)
+		require.NoError(t, app.Run([]string{"app", "-h"}))
+		requireGolden(t, "sorted-categories-help-output.txt", out())
+	})
+
+	t.Run("introspection", func(t *testing.T) {
+		app, _ := newApp()
+		var categories []string
+		for _, sub := range app.Subcommands() {
+			categories = append(categories, sub.Category())
+		}
+		require.Equal(t, []string{"", "Cluster management", "Debugging", "Cluster management", "Debugging", "Debugging", ""}, categories)
+	})
+}
+
+func TestUnknownCommandCategory(t *testing.T) {
+	app, _ := testApp(t, "app")
+	app.Categories = []string{"Debugging", "Nope"}
+	app.Command("logs", "", nil, Category("Debugging"))
+
+	require.EqualError(t, app.Validate().Err(), `app: unknown command category "Nope"`)
+}
@@ -13,6 +13,10 @@ import (
 type App struct {
 	*Cmd
 	version *cliVersion
+	debug   string
+
+	responseFiles bool
+	sources       []ArgSource
 }
 
 type cliVersion struct {
@@ -34,7 +38,10 @@ func NewApp(name, desc string) *App {
 			desc:          desc,
 			optionsIdx:    map[string]*container.Container{},
 			argsIdx:       map[string]*container.Container{},
+			literalsIdx:   map[string]*container.Container{},
 			ErrorHandling: flag.ExitOnError,
+
+			suggestionDistance: defaultSuggestionDistance,
 		},
 	}
 }
@@ -45,8 +52,11 @@ func NewApp(name, desc string) *App {
 //	Usage: appName --$name
 //	$version
 func (a *App) Version(name, version string) {
-	a.Option(name, "Show the version and exit").Bool(false)
+	a.Option(name, "Show the version and exit").NoEnv().Bool(false)
 	names := mkOptStrs(name)
+	if len(names) == 0 {
+		return
+	}
 	option := a.optionsIdx[names[0]]
 	a.version = &cliVersion{version, option}
 }
@@ -59,6 +69,10 @@ func (a *App) run(args []string) error {
 		a.onError(errVersionRequested)
 		return nil
 	}
+	if a.debugRequested(args) {
+		return a.runDebug(args[1:])
+	}
+	a.inheritedGiven = map[*container.Container]bool{}
 	return a.Cmd.run(args)
 }
 
@@ -79,14 +93,58 @@ func (a *App) PrintVersion() {
 Run uses the app configuration (specs, commands, ...) to parse the args slice
 and to execute the matching command.
 
-In case of an incorrect usage, and depending on the configured ErrorHandling policy,
-it may return an error, panic or exit
+In case of an incorrect usage or of an invalid command definition, and depending on the configured
+ErrorHandling policy, it may return an error, panic or exit
 */
 func (a *App) Run(args []string) error {
 	if err := a.doInit(); err != nil {
-		panic(err)
+		return a.initFailed(err)
+	}
+	args, err := a.expandArgs(args[1:])
+	if err != nil {
+		_, _ = fmt.Fprintf(stdErr, "error: %s\n", err.Error())
+		a.onError(err)
+		return err
+	}
+	return a.run(args)
+}
+
+/*
+Validate checks the definition of the app and all its commands, without parsing any input.
+
+Every command initializer is called, and the declaration mistakes (duplicate or invalid names) and spec errors
+are collected in the Errors of the result as *DefinitionError, each one carrying the path of the faulty command.
+
+The specs of the commands without errors are then checked for constructs which are valid but parse in surprising
+ways, which are collected in the Warnings of the result:
+
+- ambiguous specs, where the same input can be assigned to different arguments, e.g. [SRC...] [DST...]
+- arguments which can never be reached because the alternatives tried before them always match
+- options which are shadowed by another alternative, e.g. [-a | [-a]]
+- repeated options bound to env vars, which can loop forever
+- declared arguments which are not used in the spec
+
+Initializers are only ever called once: calling Run after Validate reuses the already initialized commands.
+*/
+func (a *App) Validate() Validation {
+	errs := a.validate()
+	return Validation{
+		Errors:   errs,
+		Warnings: a.lint(),
 	}
-	return a.run(args[1:])
+}
+
+// Validation is the result of App.Validate
+type Validation struct {
+	// Errors are the mistakes in the definition of the app, which make Run fail
+	Errors []error
+	// Warnings are the specs which are valid but most likely do not parse as intended
+	Warnings []*SpecWarning
+}
+
+// Err returns the Errors as a MultiError, or nil if the definition of the app is sound
+func (v Validation) Err() error {
+	return joinErrors(v.Errors)
 }
 
 // ActionCommand is a convenience function to configure a command with an action.
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// testApp returns an app which returns its errors instead of exiting, and the buffer its messages are written to
+func testApp(t *testing.T, name string) (*App, *bytes.Buffer) {
+	var out bytes.Buffer
+	prev := stdErr
+	stdErr = &out
+	t.Cleanup(func() {
+		stdErr = prev
+	})
+
+	app := NewApp(name, "")
+	app.ErrorHandling = flag.ContinueOnError
+	return app, &out
+}
+
+// requireGolden compares got with the content of testdata/name, which is rewritten instead with -update
+func requireGolden(t *testing.T, name, got string) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, []byte(got), 0644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(want), got)
+}
+
+func TestValidate(t *testing.T) {
+	app, _ := testApp(t, "app")
+	app.Command("cp", "", func(cmd *Cmd) {
+		cmd.Spec = "[SRC...] [DST...]"
+		cmd.Argument("SRC", "").StringSlice(nil)
+		cmd.Argument("DST", "").StringSlice(nil)
+	})
+	app.Command("rm", "", func(cmd *Cmd) {
+		cmd.Spec = "FILE"
+	})
+
+	v := app.Validate()
+
+	require.Len(t, v.Errors, 1)
+	require.Error(t, v.Err())
+	defErr, ok := v.Errors[0].(*DefinitionError)
+	require.True(t, ok)
+	require.Equal(t, "app rm", defErr.Path)
+
+	var warnings []string
+	for _, w := range v.Warnings {
+		warnings = append(warnings, w.String())
+	}
+	require.Equal(t, []string{
+		"app cp: argument DST can never be reached: the alternatives before it always match",
+		"app cp: ambiguous spec: the same input can be assigned to either SRC or DST",
+	}, warnings)
+}
+
+func TestValidateSoundApp(t *testing.T) {
+	app, _ := testApp(t, "app")
+	app.Command("cp", "", func(cmd *Cmd) {
+		cmd.Spec = "SRC... DST"
+		cmd.Argument("SRC", "").StringSlice(nil)
+		cmd.Argument("DST", "").String("")
+	})
+
+	v := app.Validate()
+
+	require.NoError(t, v.Err())
+	require.Empty(t, v.Warnings)
+}
+
+func TestValidateBlankCommandName(t *testing.T) {
+	app, _ := testApp(t, "app")
+	app.Command(" ", "", nil)
+
+	require.EqualError(t, app.Validate().Err(), `app: command with no name: " "`)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/duanqy/cli/internal/container"
 	"github.com/duanqy/cli/internal/fsm"
 	"github.com/duanqy/cli/internal/lexer"
+	"github.com/duanqy/cli/internal/lint"
 	"github.com/duanqy/cli/internal/parser"
 	"io"
 	"strings"
@@ -33,6 +34,14 @@ type Cmd struct {
 	LongDesc string
 	// The command error handling strategy
 	ErrorHandling flag.ErrorHandling
+	// The name of the sub command to run when the args do not name one
+	DefaultCommand string
+	// Whether the args rejected by a sub command are given to this command spec and Action instead
+	Fallthrough bool
+	// Whether the unknown options and extra arguments are collected in Context.Rest instead of being rejected
+	Passthrough bool
+	// The order of the command categories in the help, the categories not listed coming after in declaration order
+	Categories []string
 
 	init    CmdInitializer
 	name    string
@@ -45,9 +54,74 @@ type Cmd struct {
 	args       []*container.Container
 	argsIdx    map[string]*container.Container
 
+	literalsIdx map[string]*container.Container
+
 	parent *Cmd
 
-	fsm *fsm.State
+	fsm    *fsm.State
+	tokens []*lexer.Token
+	rest   []string
+	dashed []string
+
+	external        string
+	externalsListed bool
+	externalNames   []string
+
+	hidden      bool
+	deprecated  bool
+	replacement string
+	renamedTo   string
+	category    string
+
+	envPrefix          string
+	suggestionDistance int
+	singleDash         bool
+	abbreviations      bool
+	strictEnvVar       string
+	externals          bool
+	pluginDirs         []string
+	shellAliases       bool
+	sortCommands       bool
+
+	loadAliases   func() (map[string]string, error)
+	aliasesLoaded bool
+	aliasDefs     map[string]string
+
+	// the persistent options given after a sub command name in the current run
+	inheritedGiven map[*container.Container]bool
+
+	initialized bool
+	declErrs    []error
+	initErrs    []error
+	envErrs     []error
+}
+
+// CommandOption configures how a command is shown and run, see Hidden, Deprecated, Renamed and Category
+type CommandOption func(*Cmd)
+
+// Hidden leaves the command out of the help and of the suggestions, while still letting it run
+func Hidden() CommandOption {
+	return func(c *Cmd) {
+		c.hidden = true
+	}
+}
+
+// Deprecated makes the command print a warning pointing to its replacement, e.g. "app deploy", when run.
+// The replacement can be empty.
+func Deprecated(replacement string) CommandOption {
+	return func(c *Cmd) {
+		c.deprecated = true
+		c.replacement = replacement
+	}
+}
+
+// Renamed turns the command into the old name of its sibling newName: it is hidden, and running it prints a notice
+// and runs newName with the same args
+func Renamed(newName string) CommandOption {
+	return func(c *Cmd) {
+		c.hidden = true
+		c.renamedTo = newName
+	}
 }
 
 // CmdInitializer is a function that configures a command by adding options, arguments, a spec, sub commands and the code
@@ -62,11 +136,16 @@ type CmdInitializer func(*Cmd)
 //	Commands:
 //	  $name	$desc
 //
-// the last argument, init, is a function that will be called by mow.cli to further configure the created
-// (sub) command, e.g. to add options, arguments and the code to execute
-func (c *Cmd) Command(name, desc string, init CmdInitializer) {
+// the init argument is a function that will be called by mow.cli to further configure the created
+// (sub) command, e.g. to add options, arguments and the code to execute, and the options, e.g. Hidden(), change how
+// the command is shown and run
+func (c *Cmd) Command(name, desc string, init CmdInitializer, opts ...CommandOption) {
 	aliases := strings.Fields(name)
-	c.commands = append(c.commands, &Cmd{
+	if len(aliases) == 0 {
+		c.declErrs = append(c.declErrs, fmt.Errorf("command with no name: %q", name))
+		return
+	}
+	sub := &Cmd{
 		ErrorHandling: c.ErrorHandling,
 		name:          aliases[0],
 		aliases:       aliases,
@@ -77,17 +156,30 @@ func (c *Cmd) Command(name, desc string, init CmdInitializer) {
 		optionsIdx:    map[string]*container.Container{},
 		args:          []*container.Container{},
 		argsIdx:       map[string]*container.Container{},
+		literalsIdx:   map[string]*container.Container{},
 		parent:        c,
-	})
+	}
+	for _, opt := range opts {
+		opt(sub)
+	}
+	c.commands = append(c.commands, sub)
 }
 
 func (c *Cmd) doInit() error {
+	if c.initialized {
+		return joinErrors(c.initErrs)
+	}
+	c.initialized = true
+
 	if c.init != nil {
 		c.init(c)
 	}
 
+	c.recordDefaults()
+	c.loadEnvVars()
+
 	if len(c.Spec) == 0 {
-		if len(c.options) > 0 {
+		if inherited, _ := c.inheritedOptions(); len(c.options) > 0 || len(inherited) > 0 {
 			c.Spec = "[OPTIONS] "
 		}
 		for _, arg := range c.args {
@@ -95,26 +187,129 @@ func (c *Cmd) doInit() error {
 		}
 	}
 
+	errs := append([]error{}, c.declErrs...)
+	errs = append(errs, c.checkOptions()...)
+	errs = append(errs, c.checkCommands()...)
+	if err := c.prepareFsm(); err != nil {
+		errs = append(errs, err)
+	}
+
+	path := c.fullPath()
+	for _, err := range errs {
+		c.initErrs = append(c.initErrs, &DefinitionError{Path: path, Err: err})
+	}
+	return joinErrors(c.initErrs)
+}
+
+func (c *Cmd) prepareFsm() error {
 	tokens, err := lexer.Tokenize(c.Spec)
 	if err != nil {
 		return err
 	}
 
-	params := parser.Params{
-		Spec:       c.Spec,
-		Options:    c.options,
-		OptionsIdx: c.optionsIdx,
-		Args:       c.args,
-		ArgsIdx:    c.argsIdx,
-	}
-	s, err := parser.Parse(tokens, params)
+	s, err := parser.Parse(tokens, c.parserParams())
 	if err != nil {
 		return err
 	}
 	c.fsm = s
+	c.tokens = tokens
 	return nil
 }
 
+func (c *Cmd) parserParams() parser.Params {
+	options, optionsIdx := c.specOptions()
+	return parser.Params{
+		Spec:        c.Spec,
+		Options:     options,
+		OptionsIdx:  optionsIdx,
+		Args:        c.args,
+		ArgsIdx:     c.argsIdx,
+		LiteralsIdx: c.literalsIdx,
+
+		SingleDashLongOptions: c.root().singleDash,
+	}
+}
+
+// checkOptions reports the options of the spec of c which cannot be told apart, i.e. several numeric shorthands
+func (c *Cmd) checkOptions() []error {
+	var (
+		errs      []error
+		shorthand *container.Container
+	)
+	options, _ := c.specOptions()
+	for _, opt := range options {
+		if !opt.NumericShorthand {
+			continue
+		}
+		if shorthand != nil {
+			errs = append(errs, fmt.Errorf("options %s and %s both have a numeric shorthand", shorthand.Names[0], opt.Names[0]))
+			continue
+		}
+		shorthand = opt
+	}
+	return errs
+}
+
+func (c *Cmd) checkCommands() []error {
+	var errs []error
+	if c.DefaultCommand != "" && c.subcommand(c.DefaultCommand) == nil {
+		errs = append(errs, fmt.Errorf("unknown default command %q", c.DefaultCommand))
+	}
+	for _, category := range c.Categories {
+		if !c.hasCategory(category) {
+			errs = append(errs, fmt.Errorf("unknown command category %q", category))
+		}
+	}
+	seen := map[string]bool{}
+	for _, sub := range c.commands {
+		if sub.renamedTo != "" {
+			switch target := c.subcommand(sub.renamedTo); {
+			case target == nil:
+				errs = append(errs, fmt.Errorf("command %q renamed to unknown command %q", sub.name, sub.renamedTo))
+			case target.renamedTo != "":
+				errs = append(errs, fmt.Errorf("command %q renamed to %q, which is renamed too", sub.name, sub.renamedTo))
+			}
+		}
+		for _, alias := range sub.aliases {
+			if seen[alias] {
+				errs = append(errs, fmt.Errorf("duplicate command name %q", alias))
+			}
+			seen[alias] = true
+		}
+	}
+	return errs
+}
+
+// validate initializes c and all its sub commands, returning all the definition errors found along the way
+func (c *Cmd) validate() []error {
+	_ = c.doInit()
+	errs := c.initErrs
+	for _, sub := range c.commands {
+		errs = append(errs, sub.validate()...)
+	}
+	return errs
+}
+
+func (c *Cmd) lint() []*SpecWarning {
+	var res []*SpecWarning
+	if c.fsm != nil {
+		path := c.fullPath()
+		for _, msg := range lint.Check(c.fsm, c.args) {
+			res = append(res, &SpecWarning{Path: path, Msg: msg})
+		}
+	}
+	for _, sub := range c.commands {
+		res = append(res, sub.lint()...)
+	}
+	return res
+}
+
+func (c *Cmd) initFailed(err error) error {
+	_, _ = fmt.Fprintf(stdErr, "error: %s\n", err.Error())
+	c.onError(err)
+	return err
+}
+
 func (c *Cmd) onError(err error) {
 	if err == errHelpRequested || err == errVersionRequested {
 		if c.ErrorHandling == flag.ExitOnError {
@@ -159,23 +354,78 @@ func (c *Cmd) callAfter(err error) error {
 }
 
 func (c *Cmd) run(args []string) (err error) {
+	if c.external != "" {
+		return c.runExternal(args)
+	}
+	if c.renamedTo != "" {
+		return c.runRenamed(args)
+	}
+	if c.deprecated {
+		c.warnDeprecated()
+	}
+	if c.parent == nil {
+		var ran bool
+		args, ran, err = c.expandAliases(args)
+		switch {
+		case ran:
+			return err
+		case err != nil:
+			_, _ = fmt.Fprintf(stdErr, "error: %s\n", err.Error())
+			c.onError(err)
+			return err
+		}
+	}
+
+	args, err = c.normalizeArgs(args)
+	if err != nil {
+		_, _ = fmt.Fprintf(stdErr, "error: %s\n", err.Error())
+		c.PrintHelp()
+		c.onError(err)
+		return err
+	}
 	if c.helpRequested(args) {
 		c.PrintLongHelp()
 		c.onError(errHelpRequested)
 		return nil
 	}
+	if len(c.envErrs) > 0 {
+		err = joinErrors(c.envErrs)
+		_, _ = fmt.Fprintf(stdErr, "error: %s\n", err.Error())
+		c.onError(err)
+		return err
+	}
+
+	if args, err = c.extractInherited(args); err != nil {
+		_, _ = fmt.Fprintf(stdErr, "error: %s\n", err.Error())
+		c.PrintHelp()
+		c.onError(err)
+		return err
+	}
 
-	nargsLen := c.getOptsAndArgs(args)
+	nargsLen, sub, subArgs := c.dispatch(args)
 
-	if err := c.fsm.Parse(args[:nargsLen]); err != nil {
+	for _, lit := range c.literalsIdx {
+		_ = lit.Value.Set("false")
+	}
+	c.dashed = afterDoubleDash(args[:nargsLen])
+	if parsed, err := c.parse(args[:nargsLen]); err != nil {
+		if explained := parser.Explain(c.tokens, c.parserParams(), parsed); explained != nil {
+			err = explained
+		}
+		uerr, isUsage := err.(*fsm.UsageError)
+		if isUsage {
+			c.suggest(uerr)
+		}
 		_, _ = fmt.Fprintf(stdErr, "error: %s\n", err.Error())
+		if isUsage {
+			c.printUsageError(uerr)
+		}
 		c.PrintHelp()
 		c.onError(err)
 		return err
 	}
 
-	args = args[nargsLen:]
-	if len(args) == 0 {
+	if sub == nil {
 		if c.Action != nil {
 			if err = c.callBefore(); err != nil {
 				return err
@@ -190,30 +440,35 @@ func (c *Cmd) run(args []string) (err error) {
 		return nil
 	}
 
-	arg := args[0]
-	for _, sub := range c.commands {
-		if sub.isAlias(arg) {
-			if err := sub.doInit(); err != nil {
-				panic(err)
-			}
-			return sub.run(args[1:])
-		}
+	if err := sub.doInit(); err != nil {
+		return sub.initFailed(err)
 	}
+	return sub.run(subArgs)
+}
 
-	switch {
-	case strings.HasPrefix(arg, "-"):
-		err = fmt.Errorf("error: illegal option %s", arg)
-		_, _ = fmt.Fprintln(stdErr, err.Error())
-	default:
-		err = fmt.Errorf("error: illegal input %s", arg)
-		_, _ = fmt.Fprintln(stdErr, err.Error())
+// printUsageError shows the command line with the offending arg underlined, e.g.:
+//
+//	app cp a b foo
+//	           ^^^
+func (c *Cmd) printUsageError(err *fsm.UsageError) {
+	line := c.fullPath()
+	col, width := len(line)+1, 1
+	for i, arg := range err.Args {
+		if i == err.Pos {
+			col, width = len(line)+1, len(arg)
+		}
+		line += " " + arg
 	}
-	c.PrintHelp()
-	c.onError(err)
-	return err
+	if err.Pos >= len(err.Args) {
+		col = len(line) + 1
+	}
+	_, _ = fmt.Fprintf(stdErr, "\n  %s\n  %s%s\n", line, strings.Repeat(" ", col), strings.Repeat("^", width))
 }
 
 func (c *Cmd) helpRequested(args []string) bool {
+	if c.root().singleDash {
+		return c.isFlagSet(args, []string{"-h", "--help", "-help"})
+	}
 	return c.isFlagSet(args, []string{"-h", "--help"})
 }
 
@@ -231,18 +486,33 @@ func (c *Cmd) isFlagSet(args []string, searchArgs []string) bool {
 	return false
 }
 
-func (c *Cmd) getOptsAndArgs(args []string) int {
-	consumed := 0
+/*
+getOptsAndArgs returns the number of args which belong to c, the next one, if any, being the name of a sub command.
 
-	for _, arg := range args {
-		for _, sub := range c.commands {
-			if sub.isAlias(arg) {
-				return consumed
-			}
+A sub command name is only recognized where the spec of c is complete, so that an option value or an argument which
+happens to be a command name, e.g. in app --name run run, is not mistaken for one. If the spec can not be completed
+anywhere, the args are cut at the first command name for the parse error to be reported.
+*/
+func (c *Cmd) getOptsAndArgs(args []string) int {
+	first := -1
+	for i, arg := range args {
+		if c.subcommand(arg) == nil {
+			continue
 		}
-		consumed++
+		if first < 0 {
+			first = i
+		}
+		if _, ok := c.fsm.Match(args[:i]); ok {
+			return i
+		}
+	}
+	if first < 0 {
+		return len(args)
 	}
-	return consumed
+	if _, ok := c.fsm.Match(args); ok {
+		return len(args)
+	}
+	return first
 }
 
 func (c *Cmd) isAlias(arg string) bool {
@@ -254,6 +524,16 @@ func (c *Cmd) isAlias(arg string) bool {
 	return false
 }
 
+// subcommand returns the sub command of c with the given name or alias, or nil
+func (c *Cmd) subcommand(name string) *Cmd {
+	for _, sub := range c.commands {
+		if sub.isAlias(name) {
+			return sub
+		}
+	}
+	return nil
+}
+
 func joinStrings(parts ...string) string {
 	res := ""
 	for _, part := range parts {
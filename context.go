@@ -2,6 +2,8 @@ package cli
 
 import (
 	"time"
+
+	"github.com/duanqy/cli/internal/lexer"
 )
 
 type context struct {
@@ -30,6 +32,34 @@ func (c context) Option(name string) Value {
 	panic("implement me")
 }
 
+func (c context) Literal(word string) bool {
+	lit, ok := c.cmd.literalsIdx[word]
+	return ok && lit.Value.String() == "true"
+}
+
+func (c context) Literals() []string {
+	var res []string
+	seen := map[string]bool{}
+	for _, tk := range c.cmd.tokens {
+		if tk.Typ != lexer.TTLiteral || seen[tk.Val] {
+			continue
+		}
+		seen[tk.Val] = true
+		if c.Literal(tk.Val) {
+			res = append(res, tk.Val)
+		}
+	}
+	return res
+}
+
+func (c context) Rest() []string {
+	return c.cmd.rest
+}
+
+func (c context) AfterDoubleDash() []string {
+	return c.cmd.dashed
+}
+
 func newContext(cmd *Cmd) *context {
 	return &context{cmd: cmd}
 }
@@ -61,4 +91,13 @@ type Context interface {
 	Prompt(name string, desc string) Value
 	Permit(ask string) bool
 	Error() error
+	// Literal returns true if the given keyword of the spec was matched
+	Literal(word string) bool
+	// Literals returns the keywords of the spec which were matched, in the order they appear in the spec
+	Literals() []string
+	// Rest returns the unknown options and the extra arguments collected by a command in passthrough mode, in the
+	// order they were given
+	Rest() []string
+	// AfterDoubleDash returns the args given after the -- operator
+	AfterDoubleDash() []string
 }
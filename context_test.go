@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiterals(t *testing.T) {
+	app, _ := testApp(t, "app")
+	app.Spec = "(start | stop | restart) NAME"
+	name := app.Argument("NAME", "").String("")
+
+	var (
+		restart  bool
+		literals []string
+	)
+	app.Action = func(ctx Context) error {
+		restart = ctx.Literal("restart")
+		literals = ctx.Literals()
+		return nil
+	}
+
+	require.NoError(t, app.Run([]string{"app", "restart", "web"}))
+	require.True(t, restart)
+	require.Equal(t, []string{"restart"}, literals)
+	require.Equal(t, "web", *name)
+
+	// the keywords matched by the previous run must not leak into this one
+	require.NoError(t, app.Run([]string{"app", "stop", "db"}))
+	require.False(t, restart)
+	require.Equal(t, []string{"stop"}, literals)
+	require.Equal(t, "db", *name)
+}
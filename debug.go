@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/duanqy/cli/internal/fsm/fsmdot"
+)
+
+/*
+Debug enables a hidden command to troubleshoot the specs of the app, e.g. with the name "__debug":
+
+	app __debug spec [--mermaid] [COMMAND...]
+
+prints the state machine built from the spec of the app or of the given command as a Graphviz dot graph,
+or as a Mermaid flowchart, and
+
+	app __debug trace [ARG...]
+
+logs every transition tried while matching the args against the specs of the app and its commands, without
+running any action.
+
+The command is not shown in the help messages.
+*/
+func (a *App) Debug(name string) {
+	a.debug = name
+}
+
+func (a *App) debugRequested(args []string) bool {
+	return a.debug != "" && len(args) > 0 && args[0] == a.debug
+}
+
+func (a *App) runDebug(args []string) error {
+	var err error
+	switch {
+	case len(args) > 0 && args[0] == "spec":
+		err = a.debugSpec(args[1:])
+	case len(args) > 0 && args[0] == "trace":
+		err = a.debugTrace(args[1:])
+	default:
+		err = fmt.Errorf("usage: %s %s (spec [--mermaid] [COMMAND...] | trace [ARG...])", a.name, a.debug)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(stdErr, "error: %s\n", err.Error())
+		a.onError(err)
+	}
+	return err
+}
+
+func (a *App) debugSpec(args []string) error {
+	var (
+		cmd     = a.Cmd
+		mermaid = false
+	)
+	for _, arg := range args {
+		if arg == "--mermaid" {
+			mermaid = true
+			continue
+		}
+		sub := cmd.subcommand(arg)
+		if sub == nil {
+			return fmt.Errorf("unknown command %q in %s", arg, cmd.fullPath())
+		}
+		if err := sub.doInit(); err != nil {
+			return err
+		}
+		cmd = sub
+	}
+
+	if mermaid {
+		_, _ = fmt.Fprint(stdOut, fsmdot.Mermaid(cmd.fsm))
+	} else {
+		_, _ = fmt.Fprint(stdOut, fsmdot.Dot(cmd.fsm))
+	}
+	return nil
+}
+
+func (a *App) debugTrace(args []string) error {
+	cmd := a.Cmd
+	for {
+		var err error
+		if args, err = cmd.normalizeArgs(args); err != nil {
+			return err
+		}
+		rest, inherited, err := cmd.splitInherited(args)
+		if err != nil {
+			return err
+		}
+		for _, in := range inherited {
+			_, _ = fmt.Fprintf(stdOut, "# inherited %s %q\n", in.name, in.value)
+		}
+		args = rest
+		n, sub, subArgs := cmd.dispatch(args)
+		_, _ = fmt.Fprintf(stdOut, "# %s %q\n", cmd.fullPath(), args[:n])
+		if err := cmd.fsm.Trace(args[:n], stdOut); err != nil {
+			return err
+		}
+
+		if sub == nil {
+			_, _ = fmt.Fprintf(stdOut, "# matched %s\n", cmd.fullPath())
+			return nil
+		}
+		if sub.external != "" {
+			_, _ = fmt.Fprintf(stdOut, "# external %s %q\n", sub.external, subArgs)
+			return nil
+		}
+		if err := sub.doInit(); err != nil {
+			return err
+		}
+		cmd, args = sub, subArgs
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebug(t *testing.T) {
+	var out bytes.Buffer
+	prev := stdOut
+	stdOut = &out
+	t.Cleanup(func() {
+		stdOut = prev
+	})
+
+	app, _ := testApp(t, "app")
+	app.Debug("__debug")
+	verbose := app.Option("v verbose", "").Persistent().Bool(false)
+	app.Command("deploy", "", func(cmd *Cmd) {
+		cmd.Spec = "[-f] ENV"
+		cmd.Option("f force", "").Bool(false)
+		cmd.Argument("ENV", "").String("")
+		cmd.Action = func(ctx Context) error {
+			t.Fatal("the action must not run")
+			return nil
+		}
+	})
+
+	t.Run("dot", func(t *testing.T) {
+		out.Reset()
+		require.NoError(t, app.Run([]string{"app", "__debug", "spec", "deploy"}))
+		require.Equal(t, "digraph G {\n\trankdir=LR\n"+
+			"\tS1\n\tS1 -> S2 [label=\"-f\"]\n"+
+			"\tS2\n\tS2 -> S3 [label=\"ENV\"]\n"+
+			"\tS3 [peripheries=2]\n\tS1 -> S3 [label=\"ENV\"]\n}\n", out.String())
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		out.Reset()
+		require.NoError(t, app.Run([]string{"app", "__debug", "spec", "--mermaid", "deploy"}))
+		require.Equal(t, "flowchart LR\n"+
+			"\tS1((S1))\n\tS1 -->|\"-f\"| S2\n"+
+			"\tS2((S2))\n\tS2 -->|\"ENV\"| S3\n"+
+			"\tS3(((S3)))\n\tS1 -->|\"ENV\"| S3\n", out.String())
+	})
+
+	t.Run("trace", func(t *testing.T) {
+		out.Reset()
+		require.NoError(t, app.Run([]string{"app", "__debug", "trace", "deploy", "--verbose", "-f", "prod"}))
+		require.Equal(t, `# app []
+S1: accepted
+# inherited --verbose "true"
+# app deploy ["-f" "prod"]
+S1 ["-f" "prod"]
+  -f -> S2: matched
+  ENV -> S3: no match
+  S2 ["prod"]
+    ENV -> S3: matched
+    S3: accepted
+# matched app deploy
+`, out.String())
+		// tracing sets no value
+		require.False(t, *verbose)
+	})
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+)
+
+// warnDeprecated tells the user that c is deprecated, e.g.:
+//
+//	warning: 'app up' is deprecated, use 'app deploy' instead
+func (c *Cmd) warnDeprecated() {
+	msg := fmt.Sprintf("warning: '%s' is deprecated", c.fullPath())
+	if c.replacement != "" {
+		msg += fmt.Sprintf(", use '%s' instead", c.replacement)
+	}
+	_, _ = fmt.Fprintln(stdErr, msg)
+}
+
+// runRenamed runs the command c was renamed to with args, after a notice
+func (c *Cmd) runRenamed(args []string) error {
+	target := c.parent.subcommand(c.renamedTo)
+	_, _ = fmt.Fprintf(stdErr, "note: '%s' was renamed to '%s'\n", c.fullPath(), target.fullPath())
+	if err := target.doInit(); err != nil {
+		return target.initFailed(err)
+	}
+	return target.run(args)
+}
+
+// deprecationNote returns the note shown next to the description of c in the help of its parent, if deprecated
+func (c *Cmd) deprecationNote() string {
+	switch {
+	case !c.deprecated:
+		return ""
+	case c.replacement != "":
+		return fmt.Sprintf("(deprecated, use '%s')", c.replacement)
+	default:
+		return "(deprecated)"
+	}
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHiddenDeprecatedRenamedCommands(t *testing.T) {
+	var (
+		ran    string
+		force  bool
+		newApp = func() (*App, func() string) {
+			app, out := testApp(t, "app")
+			app.Command("deploy", "Deploy the app", func(cmd *Cmd) {
+				cmd.Option("f force", "").BoolVar(&force, false)
+				cmd.Action = func(ctx Context) error {
+					ran = "deploy"
+					return nil
+				}
+			})
+			app.Command("up", "Bring the app up", ActionCommand(func(ctx Context) error {
+				ran = "up"
+				return nil
+			}), Deprecated("app deploy"))
+			app.Command("old", "Old stuff", ActionCommand(func(ctx Context) error {
+				ran = "old"
+				return nil
+			}), Deprecated(""))
+			app.Command("push", "", nil, Renamed("deploy"))
+			app.Command("secret", "Secret", ActionCommand(func(ctx Context) error {
+				ran = "secret"
+				return nil
+			}), Hidden())
+			return app, out.String
+		}
+	)
+
+	cases := []struct {
+		args  []string
+		ran   string
+		force bool
+		msg   string
+	}{
+		{args: []string{"up"}, ran: "up", msg: "warning: 'app up' is deprecated, use 'app deploy' instead\n"},
+		{args: []string{"old"}, ran: "old", msg: "warning: 'app old' is deprecated\n"},
+		{args: []string{"push", "-f"}, ran: "deploy", force: true, msg: "note: 'app push' was renamed to 'app deploy'\n"},
+		{args: []string{"secret"}, ran: "secret"},
+		{args: []string{"deploy"}, ran: "deploy"},
+	}
+	for _, cas := range cases {
+		t.Run(cas.args[0], func(t *testing.T) {
+			ran, force = "", false
+			app, out := newApp()
+
+			require.NoError(t, app.Run(append([]string{"app"}, cas.args...)))
+			require.Equal(t, cas.ran, ran)
+			require.Equal(t, cas.force, force)
+			require.Equal(t, cas.msg, out())
+		})
+	}
+
+	t.Run("help", func(t *testing.T) {
+		app, out := newApp()
+		require.NoError(t, app.Run([]string{"app", "-h"}))
+		requireGolden(t, "hidden-deprecated-help-output.txt", out())
+	})
+
+	t.Run("suggestions", func(t *testing.T) {
+		app, out := newApp()
+		require.Error(t, app.Run([]string{"app", "secre"}))
+		require.NotContains(t, out(), "did you mean")
+
+		app, out = newApp()
+		require.Error(t, app.Run([]string{"app", "pus"}))
+		require.NotContains(t, out(), "did you mean")
+	})
+
+	t.Run("introspection", func(t *testing.T) {
+		app, _ := newApp()
+		cmds := map[string]*Cmd{}
+		for _, sub := range app.Subcommands() {
+			cmds[sub.Name()] = sub
+		}
+
+		require.True(t, cmds["secret"].Hidden())
+		require.True(t, cmds["push"].Hidden())
+		require.False(t, cmds["up"].Hidden())
+		deprecated, replacement := cmds["up"].Deprecated()
+		require.True(t, deprecated)
+		require.Equal(t, "app deploy", replacement)
+		require.Equal(t, "deploy", cmds["push"].RenamedTo())
+	})
+}
+
+func TestRenamedCommandsValidation(t *testing.T) {
+	app, _ := testApp(t, "app")
+	app.Command("a", "", nil, Renamed("b"))
+	app.Command("b", "", nil, Renamed("a"))
+	app.Command("c", "", nil, Renamed("nope"))
+
+	require.EqualError(t, app.Validate().Err(), `app: command "a" renamed to "b", which is renamed too
+app: command "b" renamed to "a", which is renamed too
+app: command "c" renamed to unknown command "nope"`)
+
+	require.Error(t, app.Run([]string{"app", "a"}))
+}
@@ -0,0 +1,93 @@
+package cli
+
+/*
+dispatch splits args between c and one of its sub commands: it returns the number of args which belong to c, and the
+sub command to run with its args, if any.
+
+When the args name no known sub command, an external command can be named where the spec of c is complete and it
+does not accept all the args. Otherwise the default command is run with the args which remain after the longest prefix
+accepted by the spec of c, e.g. its options. With Fallthrough, if the sub command rejects its args and the spec of c
+accepts all of them, including the command name, c runs instead.
+*/
+func (c *Cmd) dispatch(args []string) (int, *Cmd, []string) {
+	var (
+		n       = c.getOptsAndArgs(args)
+		sub     *Cmd
+		subArgs []string
+	)
+	if n < len(args) {
+		sub, subArgs = c.subcommand(args[n]), args[n+1:]
+	} else if i, ext := c.findExternal(args); ext != nil {
+		n, sub, subArgs = i, ext, args[i+1:]
+	} else if c.DefaultCommand != "" {
+		n = c.longestPrefix(args)
+		sub, subArgs = c.subcommand(c.DefaultCommand), args[n:]
+	}
+	if sub != nil && sub.external == "" && c.Fallthrough && c.fallsThrough(sub, subArgs, args) {
+		return len(args), nil, nil
+	}
+	return n, sub, subArgs
+}
+
+// findExternal looks for the name of an external command in args, returning its position and the command, if any
+func (c *Cmd) findExternal(args []string) (int, *Cmd) {
+	if !c.root().externals {
+		return 0, nil
+	}
+	if _, ok := c.fsm.Match(args); ok {
+		return 0, nil
+	}
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if _, ok := c.fsm.Match(args[:i]); !ok {
+			continue
+		}
+		if ext := c.externalCommand(arg); ext != nil {
+			return i, ext
+		}
+	}
+	return 0, nil
+}
+
+// longestPrefix returns the length of the longest prefix of args accepted by the spec of c
+func (c *Cmd) longestPrefix(args []string) int {
+	for i := len(args); i > 0; i-- {
+		if _, ok := c.fsm.Match(args[:i]); ok {
+			return i
+		}
+	}
+	return 0
+}
+
+// fallsThrough checks whether args should be given to c rather than to its sub command sub, which rejects subArgs
+func (c *Cmd) fallsThrough(sub *Cmd, subArgs, args []string) bool {
+	if c.Action == nil {
+		return false
+	}
+	if _, ok := c.fsm.Match(args); !ok {
+		return false
+	}
+	if err := sub.doInit(); err != nil {
+		return false
+	}
+	return !sub.accepts(subArgs)
+}
+
+// accepts checks, without setting any value, whether c or one of its sub commands can parse args
+func (c *Cmd) accepts(args []string) bool {
+	args, err := c.normalizeArgs(args)
+	if err != nil {
+		return false
+	}
+	if c.helpRequested(args) {
+		return true
+	}
+	if args, _, err = c.splitInherited(args); err != nil {
+		return false
+	}
+	n, _, _ := c.dispatch(args)
+	_, ok := c.fsm.Match(args[:n])
+	return ok
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatch(t *testing.T) {
+	var (
+		ran     []string
+		name    string
+		verbose bool
+		image   string
+		newApp  = func() *App {
+			app, _ := testApp(t, "app")
+			app.Option("name", "").StringVar(&name, "")
+			app.Option("v verbose", "").BoolVar(&verbose, false)
+			app.Command("run", "", func(cmd *Cmd) {
+				cmd.Spec = "[IMAGE]"
+				cmd.Argument("IMAGE", "").StringVar(&image, "")
+				cmd.Action = func(ctx Context) error {
+					ran = append(ran, "run")
+					return nil
+				}
+			})
+			app.Command("job", "", func(cmd *Cmd) {
+				cmd.Command("start", "", func(cmd *Cmd) {
+					cmd.Action = func(ctx Context) error {
+						ran = append(ran, "job start")
+						return nil
+					}
+				})
+			})
+			return app
+		}
+	)
+
+	cases := []struct {
+		args    []string
+		ran     []string
+		name    string
+		verbose bool
+		image   string
+	}{
+		{args: []string{"run"}, ran: []string{"run"}},
+		{args: []string{"run", "img"}, ran: []string{"run"}, image: "img"},
+		{args: []string{"-v", "run", "img"}, ran: []string{"run"}, verbose: true, image: "img"},
+		{args: []string{"--name", "x", "run"}, ran: []string{"run"}, name: "x"},
+		{args: []string{"job", "start"}, ran: []string{"job start"}},
+		{args: []string{"-v", "job", "start"}, ran: []string{"job start"}, verbose: true},
+		{args: []string{"run", "job"}, ran: []string{"run"}, image: "job"},
+		{args: []string{"--name", "run", "run"}, ran: []string{"run"}, name: "run"},
+		{args: []string{"--name", "job", "job", "start"}, ran: []string{"job start"}, name: "job"},
+		{args: []string{"--name=run", "run", "run"}, ran: []string{"run"}, name: "run", image: "run"},
+	}
+	for _, cas := range cases {
+		t.Run(fmt.Sprint(cas.args), func(t *testing.T) {
+			ran, name, verbose, image = nil, "", false, ""
+			app := newApp()
+
+			require.NoError(t, app.Run(append([]string{"app"}, cas.args...)))
+			require.Equal(t, cas.ran, ran)
+			require.Equal(t, cas.name, name)
+			require.Equal(t, cas.verbose, verbose)
+			require.Equal(t, cas.image, image)
+		})
+	}
+}
+
+func TestDefaultCommand(t *testing.T) {
+	var (
+		ran  string
+		port int
+		dir  string
+	)
+	newApp := func() *App {
+		app, _ := testApp(t, "app")
+		app.DefaultCommand = "serve"
+		app.Option("d dir", "").StringVar(&dir, ".")
+		app.Command("serve", "", func(cmd *Cmd) {
+			cmd.Option("p port", "").IntVar(&port, 80)
+			cmd.Action = func(ctx Context) error {
+				ran = "serve"
+				return nil
+			}
+		})
+		app.Command("build", "", ActionCommand(func(ctx Context) error {
+			ran = "build"
+			return nil
+		}))
+		return app
+	}
+
+	cases := []struct {
+		args []string
+		ran  string
+		port int
+		dir  string
+	}{
+		{args: nil, ran: "serve", port: 80, dir: "."},
+		{args: []string{"serve"}, ran: "serve", port: 80, dir: "."},
+		{args: []string{"--port", "8080"}, ran: "serve", port: 8080, dir: "."},
+		{args: []string{"-d", "www", "-p", "8080"}, ran: "serve", port: 8080, dir: "www"},
+		{args: []string{"-d", "www", "serve", "-p", "8080"}, ran: "serve", port: 8080, dir: "www"},
+		{args: []string{"build"}, ran: "build", port: 0, dir: "."},
+	}
+	for _, cas := range cases {
+		t.Run(fmt.Sprint(cas.args), func(t *testing.T) {
+			ran, port, dir = "", 0, ""
+			app := newApp()
+
+			require.NoError(t, app.Run(append([]string{"app"}, cas.args...)))
+			require.Equal(t, cas.ran, ran)
+			require.Equal(t, cas.port, port)
+			require.Equal(t, cas.dir, dir)
+		})
+	}
+
+	app := newApp()
+	app.DefaultCommand = "nope"
+	require.EqualError(t, app.Validate().Err(), `app: unknown default command "nope"`)
+}
+
+func TestFallthrough(t *testing.T) {
+	var (
+		ran   string
+		files []string
+	)
+	newApp := func() *App {
+		app, _ := testApp(t, "app")
+		app.Fallthrough = true
+		app.Spec = "[FILE...]"
+		app.Argument("FILE", "").StringSliceVar(&files, nil)
+		app.Action = func(ctx Context) error {
+			ran = "app"
+			return nil
+		}
+		app.Command("stats", "", func(cmd *Cmd) {
+			cmd.Action = func(ctx Context) error {
+				ran = "stats"
+				return nil
+			}
+		})
+		return app
+	}
+
+	cases := []struct {
+		args  []string
+		ran   string
+		files []string
+	}{
+		{args: []string{"stats"}, ran: "stats"},
+		{args: []string{"stats", "notes.txt"}, ran: "app", files: []string{"stats", "notes.txt"}},
+		{args: []string{"a.txt", "b.txt"}, ran: "app", files: []string{"a.txt", "b.txt"}},
+	}
+	for _, cas := range cases {
+		t.Run(fmt.Sprint(cas.args), func(t *testing.T) {
+			ran, files = "", nil
+			app := newApp()
+
+			require.NoError(t, app.Run(append([]string{"app"}, cas.args...)))
+			require.Equal(t, cas.ran, ran)
+			require.Equal(t, cas.files, files)
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		app := newApp()
+		app.Fallthrough = false
+		require.Error(t, app.Run([]string{"app", "stats", "notes.txt"}))
+	})
+}
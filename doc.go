@@ -92,6 +92,14 @@ You can only access the values stored in the pointers in the Action func, which
 argument parsing has been completed. This precludes using the value of one
 option as the default value of another.
 
+Instead of declaring the env vars one by one, an app can bind all of its options and arguments to env vars
+derived from a prefix, the command path and their names:
+
+    app.EnvPrefix("MYAPP")
+
+With the above, the --region option of the deploy command is bound to $MYAPP_DEPLOY_REGION. An explicit env
+var declared with Env takes precedence, and NoEnv opts a parameter out.
+
 On the command line, the following syntaxes are supported when specifying
 options.
 
@@ -119,6 +127,18 @@ values in a slice:
     --env PATH:/bin --env PATH:/usr/bin  resulting slice contains ["/bin", "/usr/bin"]
     --env=PATH:/bin --env=PATH:/usr/bin  resulting slice contains ["/bin", "/usr/bin"]
 
+A slice option can also declare a delimiter with Delimiter, in which case every value is split on it.
+A part can be quoted or its delimiter escaped with a backslash to keep it whole:
+
+    --tags a,b         resulting slice contains ["a", "b"]
+    --tags 'a,b',c     resulting slice contains ["a,b", "c"]
+    --tags a\,b        resulting slice contains ["a,b"]
+
+Env var values of slice options are split on commas, or on the delimiter set with EnvDelimiter (e.g. ":" for
+PATH-like variables or "\n"), falling back to the one set with Delimiter.  Quotes and backslashes are only
+interpreted when a delimiter is set, and a value which cannot be split, e.g. with an unterminated quote, makes
+the command fail.
+
 
 
 Arguments
@@ -288,6 +308,106 @@ of ways:
     $ docker job log show
     $ docker job log clear
 
+A command name is only recognized where the spec of its parent is complete, so
+an option value which happens to be a command name is not mistaken for one:
+
+    $ docker --name run run   # runs the run command with --name=run
+
+A command can name the sub command to run when the args do not name one with
+DefaultCommand.  The command keeps the longest prefix of the args its spec
+accepts, e.g. its options, and the default command gets the rest:
+
+    app.DefaultCommand = "serve"
+
+    // Equivalent:
+    //   $ app --port 8080
+    //   $ app serve --port 8080
+
+Sub commands can also be added to an existing tool without breaking its
+invocations by setting Fallthrough: when a sub command rejects its args and
+the spec of its parent accepts all of them, command name included, the parent
+Action runs instead.  With a tool taking files, "app stats" runs the new stats
+command while "app stats notes.txt" still processes the two files.
+
+Options passed to Command change how a command is shown and run: Hidden
+leaves it out of the help and of the suggestions, Deprecated prints a warning
+pointing to its replacement when it runs, and Renamed keeps an old name working
+by forwarding to the new command with a notice:
+
+    app.Command("deploy", "Deploy the app", deploy)
+    app.Command("up", "Deploy the app", deploy, cli.Deprecated("app deploy"))
+    app.Command("push", "", nil, cli.Renamed("deploy"))
+
+    $ app push
+    note: 'app push' was renamed to 'app deploy'
+
+Apps with many commands can split the Commands section of the help with the
+Category option.  The sections follow the Categories of the parent command, then
+the declaration order, and App.SortCommands(true) lists the commands of every
+section in alphabetical order:
+
+    app.Categories = []string{"Cluster management", "Debugging"}
+    app.Command("logs", "Print the logs", logs, cli.Category("Debugging"))
+    app.Command("scale", "Scale the app", scale, cli.Category("Cluster management"))
+
+Documentation generators walking the commands can check Cmd.Hidden,
+Cmd.Deprecated, Cmd.RenamedTo and Cmd.Category.
+
+Wrappers around other tools can set Passthrough on a command: its known
+options and arguments are still parsed, but the unknown options and the extra
+arguments are collected, in order, in Context.Rest instead of being rejected.
+Context.AfterDoubleDash returns the args given after the -- operator:
+
+    app.Command("exec", "Run kubectl", func(cmd *cli.Cmd) {
+        cmd.Passthrough = true
+        ns := cmd.Option("n namespace", "The namespace").String("default")
+        cmd.Action = func(ctx cli.Context) error {
+            // app exec -n prod get --watch pods: ctx.Rest() is [get --watch pods]
+            return kubectl(*ns, ctx.Rest())
+        }
+    })
+
+Other teams can extend an app without recompiling it with git-style external
+commands.  Once enabled, an unknown command name like "app foo" runs the
+executable app-foo found in the given dirs or in the PATH with the remaining
+args.  It shares the stdio of the app, gets its signals, its exit code becomes
+the one of the app and it receives the persistent options in env vars:
+
+    app.ExternalCommands("/usr/lib/app/plugins")
+
+The external commands found are listed in an "External Commands" section of
+the help.
+
+Users can define their own shortcuts, like git aliases, in a config source
+given to App.Aliases.  AliasFile reads them from a file with one "name = args"
+line per alias:
+
+    home, _ := os.UserHomeDir()
+    app.Aliases(cli.AliasFile(filepath.Join(home, ".apprc")))
+
+    // With "co = checkout -b" in ~/.apprc:
+    //   $ app co feature   # same as app checkout -b feature
+
+An alias can expand to another alias but never to itself, and an alias named
+after a command is ignored.  Aliases starting with ! run a shell command, and
+are only allowed after a call to App.ShellAliases(true).  The aliases are
+listed in an "Aliases" section of the app help.
+
+Options declared on a command must normally appear before the name of a
+subcommand.  Marking an option as persistent makes it usable anywhere in the
+subtree of the command, before or after the arguments of the subcommands:
+
+    verbose := app.Option("v verbose", "Verbose mode").Persistent().Bool(false)
+
+    // Both work:
+    //   $ docker -v job list
+    //   $ docker job list --verbose
+
+Persistent options are part of the [OPTIONS] shortcut of the subcommands, set
+the value of the option of the ancestor and are listed in a separate "Global
+Options" section of the subcommands help.  A subcommand can still declare an
+option with the same name, which then hides the persistent one.
+
 As a convenience, to assign an Action to a func with no arguments, use
 ActionCommand when defining the Command. For example, the following two
 statements are equivalent:
@@ -412,28 +532,53 @@ assign a spec string to the Command's Spec field:
 The spec syntax is mostly based on the conventions used in POSIX command line
 applications (help messages and man pages). This syntax is described in full
 below. If a user invokes the app or command with the incorrect syntax, the app
-terminates with a help message showing the proper invocation. The remainder of
-this section describes the many features and capabilities of the spec string
-grammar.
+terminates with an error pointing at the offending argument and a help message
+showing the proper invocation:
+
+    error: unexpected argument 'foo' after DST
+
+      app cp a b foo
+                 ^^^
+
+Unknown options and commands which are close to a known one come with a
+suggestion, e.g. "unknown option --verbos, did you mean --verbose?", and options
+which belong to another command say so.  The tolerance is set with
+App.SuggestionDistance, 0 disabling the suggestions.
+
+The remainder of this section describes the many features and capabilities of
+the spec string grammar.
 
 Options can use both short and long option names in spec strings.  In the
 example below, the option is mandatory and must be provided.  Any options
-referenced in a spec string MUST be explicitly declared, otherwise this package
-will panic. I.e. for each item in the spec string, a corresponding *Opt or *Arg
-is required:
+referenced in a spec string MUST be explicitly declared, otherwise Run fails
+with a definition error. I.e. for each item in the spec string, a corresponding
+*Opt or *Arg is required:
 
     x.Spec = "-f"  // or x.Spec = "--force"
     forceFlag := x.BoolOpt("f force", ...)
 
 Arguments are specified with all-uppercased words.  In the example below, both
 SRC and DST must be provided by the user (two arguments).  Like options, any
-argument referenced in a spec string MUST be explicitly declared, otherwise this
-package will panic:
+argument referenced in a spec string MUST be explicitly declared, otherwise Run
+fails with a definition error:
 
     x.Spec="SRC DST"
     src := x.StringArg("SRC", ...)
     dst := x.StringArg("DST", ...)
 
+Keywords are specified with lower case words (letters, digits, '-' and '_').
+Unlike arguments, they do not need to be declared: the user must type them
+verbatim.  They can be combined with the other elements, e.g. to offer a choice
+between a few verbs.  The Action can tell which keywords were matched with
+ctx.Literal or ctx.Literals:
+
+    x.Spec = "(start | stop | restart) NAME"
+    x.Action = func(ctx cli.Context) error {
+        if ctx.Literal("restart") {
+            ...
+        }
+    }
+
 With the exception of options, the order of the elements in a spec string is
 respected and enforced when command line arguments are parsed.  In the example
 below, consecutive options (-f and -g) are parsed regardless of the order they
@@ -478,6 +623,13 @@ multiple -e options and multiple SRC arguments:
     //   $ app -eeeee file1 file2
     //   $ app -e -e -e -e file1 file2
 
+The number of repetitions can be bounded with the {min,max} postfix operator:
+{n} requires exactly n occurrences, {n,m} between n and m, and {n,} at least n.
+A call which does not respect the bounds fails with an error such as "expected
+at least 2 POINT, got 1":
+
+    x.Spec = "-I{0,3} POINT{2,4}"
+
 Grouping of options and arguments is specified in a spec string with
 parenthesis.  When combined with the choice | and repetition ... operators,
 complex syntaxes can be created. The parenthesis in the example below indicate a
@@ -518,12 +670,16 @@ c and d, then the following two statements are equivalent:
 
 Inline option values are specified in the spec string with the =<some-text>
 notation immediately following an option (long or short form) to provide users
-with an inline description or value. The actual inline values are ignored by the
-spec parser as they exist only to provide a contextual hint to the user. In the
-example below, "absolute-path" and "in seconds" are ignored by the parser:
+with an inline description or value. The actual inline values do not change how
+the command line is parsed: they are a contextual hint to the user, and are shown
+next to the option names in the help message. In the example below, the options
+are listed as "-a=<absolute-path>" and "--timeout=<in seconds>":
 
     x.Spec = "[ -a=<absolute-path> | --timeout=<in seconds> ] ARG"
 
+Options without an inline value in the spec are shown with a placeholder derived
+from their type, e.g. "--count=<int>". Bool options do not have one.
+
 The -- operator can be used to automatically treat everything following it as
 arguments.  In other words, placing a -- in the spec string automatically
 inserts a -- in the same position in the program call arguments. This lets you
@@ -534,6 +690,41 @@ write programs such as the POSIX time utility for example:
     // Allows parsing of the following shell command:
     //   $ app -p ps -aux
 
+Apps migrating from the standard flag package can call
+App.SingleDashLongOptions(true) to also accept long options with a single dash,
+both on the command line and in spec strings, e.g. "-timeout 5s" or
+"-verbose=true".  A single dash arg is only read as a long option if the command
+declares a long option with that name.
+
+App.AllowAbbreviations lets users type any unique prefix of a long option or of
+a command name, e.g. "--verb" for "--verbose" or "stat" for "status".  Ambiguous
+prefixes are rejected with the list of the candidates.  Scripts can turn
+abbreviations off by setting the env var given to AllowAbbreviations:
+
+    app.AllowAbbreviations("MYAPP_STRICT")
+
+Negative numbers are accepted as the values of the numeric options and
+arguments (int, int64, float64 and their slices), e.g. "--offset -10" or
+"calc -3 4", as long as the command declares no option named after a digit,
+e.g. "-1", in which case they are read as options.  A numeric option can also
+be given as a dash followed by its value, like head -5:
+
+    lines := cmd.Option("n lines", "Number of lines").NumericShorthand().Int(10)
+
+Long command lines can be moved to response files with App.ResponseFiles(true):
+an arg like @paths.txt is then replaced by the args read from the file, which
+are separated by white spaces and can be quoted like in a shell.  A # starting
+an arg comments out the rest of the line, a backslash at the end of a line
+continues it, @@ escapes a literal at-sign and response files can include
+other ones.  Errors in a response file are reported with its name
+and line, and App.ArgSources tells where each arg of the last run comes from:
+
+    $ cat paths.txt
+    # generated by the build
+    src/main.go
+    'src/with space.go'
+    $ app build @paths.txt
+
 
 
 Spec Grammar
@@ -544,14 +735,16 @@ Below is the full EBNF grammar for the Specs language:
     sequence     -> choice*
     req_sequence -> choice+
     choice       -> atom ('|' atom)*
-    atom         -> (shortOpt | longOpt | optSeq | allOpts | group | optional) rep?
+    atom         -> (shortOpt | longOpt | optSeq | allOpts | arg | keyword | group | optional) rep?
     shortOp      -> '-' [A-Za-z]
     longOpt      -> '--' [A-Za-z][A-Za-z0-9]*
     optSeq       -> '-' [A-Za-z]+
     allOpts      -> '[OPTIONS]'
+    arg          -> [A-Z][A-Z0-9_]*
+    keyword      -> [a-z][a-z0-9_-]*
     group        -> '(' req_sequence ')'
     optional     -> '[' req_sequence ']'
-    rep          -> '...'
+    rep          -> '...' | '{' [0-9]+ (',' [0-9]*)? '}'
 
 By combining a few of these building blocks together (while respecting the
 grammar above), powerful and sophisticated validation constraints can be created
@@ -572,6 +765,57 @@ does.
 
 
 
+Validation
+
+Mistakes in the definition of an app, e.g. an invalid spec string or a duplicate
+option name, are reported by Run as errors of type *DefinitionError, handled
+according to the ErrorHandling policy. As subcommands are only initialized when
+invoked, such mistakes can go unnoticed. Validate initializes the whole command
+tree and returns all of them, which makes it easy to check an app definition
+in a test:
+
+    func TestApp(t *testing.T) {
+        v := newApp().Validate()
+        if err := v.Err(); err != nil {
+            t.Fatal(err)
+        }
+        for _, w := range v.Warnings {
+            t.Error(w)
+        }
+    }
+
+Validate goes one step further and also returns, as Warnings, the specs which
+are valid but most likely do not parse as intended, e.g. "[SRC...] [DST...]"
+where DST can never be given a value.
+
+
+
+Introspection
+
+The definition of an app can be inspected, e.g. to generate documentation.
+Walk visits a command and all its subcommands, while Options and Arguments
+describe the parameters of a command: names, env var, default value, type, and
+whether they are hidden or required by the spec:
+
+    app.Walk(func(cmd *cli.Cmd) error {
+        fmt.Println(cmd.Path(), cmd.Description())
+        for _, opt := range cmd.Options() {
+            fmt.Println("  ", strings.Join(opt.Names, ", "), opt.Type, opt.Required)
+        }
+        return nil
+    })
+
+When a spec does not parse as expected, App.Debug enables a hidden command which
+prints the state machine built from a spec, as a Graphviz dot graph or a Mermaid
+flowchart, or traces how some args are matched:
+
+    app.Debug("__debug")
+
+    $ app __debug spec --mermaid deploy
+    $ app __debug trace deploy --force x y
+
+
+
 Default Spec
 
 By default an auto-generated spec string is created for the app and every
@@ -609,6 +853,13 @@ string, int, float64, strings (slice of strings), ints (slice of ints) and float
 You can, however, extend this package to handle other types, e.g. time.Duration, float64,
 or even your own struct types.
 
+The simplest way to support another type is to declare the option or argument
+with Typed (or TypedSlice for a multi-valued one), passing the functions used to
+parse a value from the command line or an env var and to render it in the help
+messages:
+
+    endpoint := cli.Typed(app.Option("u url", "The endpoint"), url.Parse, (*url.URL).String, nil)
+
 To define your own custom type, you must implement the flag.Value interface for
 your custom type, and then declare the option or argument using VarOpt or VarArg
 respectively if using the short-form methods. If using the long-form struct,
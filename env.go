@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/duanqy/cli/internal/values"
+)
+
+// EnvPrefix enables automatic env var names for all the parameters of the app and its commands.
+//
+// Parameters which do not explicitly declare an env var with Env() and were not opted out with NoEnv()
+// are bound to an env var built from the prefix, the command path and the parameter name, e.g. with
+// the prefix "MYAPP", the --region option of the "deploy" command is bound to $MYAPP_DEPLOY_REGION.
+//
+// Options with only one letter names are left untouched.
+func (a *App) EnvPrefix(prefix string) {
+	a.envPrefix = prefix
+}
+
+// loadEnvVars fills the command parameters from their env vars, the env vars which cannot be split being recorded
+// in envErrs
+func (c *Cmd) loadEnvVars() {
+	c.deriveEnvVars()
+
+	load := func(con *container.Container) {
+		switch {
+		case con.EnvVar == "":
+		case con.EnvDelimiter == "" && con.Delimiter == "":
+			con.ValueSetFromEnv = values.SetFromEnv(con.Value, con.EnvVar)
+		default:
+			var err error
+			con.ValueSetFromEnv, err = values.SetFromEnvDelimited(con.Value, con.EnvVar, envDelimiter(con))
+			if err != nil {
+				c.envErrs = append(c.envErrs, err)
+			}
+		}
+	}
+
+	for _, opt := range c.options {
+		load(opt)
+	}
+	for _, arg := range c.args {
+		load(arg)
+	}
+}
+
+func envDelimiter(con *container.Container) string {
+	switch {
+	case con.EnvDelimiter != "":
+		return con.EnvDelimiter
+	case con.Delimiter != "":
+		return con.Delimiter
+	default:
+		return ","
+	}
+}
+
+// deriveEnvVars binds the parameters which do not have an env var to their automatic name, if enabled by the app
+func (c *Cmd) deriveEnvVars() {
+	prefix := c.root().envPrefix
+	if prefix == "" {
+		return
+	}
+
+	var parts []string
+	for p := c; p.parent != nil; p = p.parent {
+		parts = append([]string{p.name}, parts...)
+	}
+	parts = append([]string{prefix}, parts...)
+
+	bind := func(con *container.Container, name string) {
+		if con.EnvVar != "" || con.NoEnv || name == "" {
+			return
+		}
+		con.EnvVar = envVarName(append(parts, name)...)
+	}
+
+	for _, opt := range c.options {
+		bind(opt, longOptName(opt))
+	}
+	for _, arg := range c.args {
+		bind(arg, arg.Name)
+	}
+}
+
+func (c *Cmd) root() *Cmd {
+	if c.parent == nil {
+		return c
+	}
+	return c.parent.root()
+}
+
+func longOptName(o *container.Container) string {
+	for _, n := range o.Names {
+		if len(n) > 2 {
+			return strings.TrimPrefix(n, "--")
+		}
+	}
+	return ""
+}
+
+func envVarName(parts ...string) string {
+	res := strings.ToUpper(strings.Join(parts, "_"))
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, res)
+}
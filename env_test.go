@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvPrefix(t *testing.T) {
+	t.Setenv("MYAPP_DEPLOY_REGION", "eu")
+	t.Setenv("MYAPP_DEPLOY_TAGS", "x,y")
+	t.Setenv("MYAPP_DEPLOY_FORCE", "true")
+	t.Setenv("MYAPP_DEPLOY_TARGET", "prod")
+
+	var (
+		region, target string
+		tags           []string
+		force          bool
+	)
+	run := func(args ...string) {
+		app, _ := testApp(t, "myapp")
+		app.EnvPrefix("myapp")
+		app.Command("deploy", "", func(cmd *Cmd) {
+			cmd.Spec = "[OPTIONS] [TARGET]"
+			cmd.Option("r region", "").StringVar(&region, "us")
+			cmd.Option("t tags", "").StringSliceVar(&tags, nil)
+			cmd.Option("f force", "").NoEnv().BoolVar(&force, false)
+			cmd.Argument("TARGET", "").StringVar(&target, "")
+			cmd.Action = func(ctx Context) error {
+				return nil
+			}
+		})
+		require.NoError(t, app.Run(append([]string{"myapp"}, args...)))
+	}
+
+	run("deploy")
+	require.Equal(t, "eu", region)
+	require.Equal(t, []string{"x", "y"}, tags)
+	require.False(t, force, "NoEnv options should not be bound")
+	require.Equal(t, "prod", target)
+
+	run("deploy", "--region", "us", "--tags", "a", "--tags", "b", "staging")
+	require.Equal(t, "us", region)
+	require.Equal(t, []string{"a", "b"}, tags)
+	require.Equal(t, "staging", target)
+
+	info := map[string]string{}
+	app, _ := testApp(t, "myapp")
+	app.EnvPrefix("myapp")
+	app.Command("deploy", "", func(cmd *Cmd) {
+		cmd.Option("region", "").String("")
+		cmd.Option("tags", "").Env("TAGS").StringSlice(nil)
+	})
+	require.NoError(t, app.Validate().Err())
+	for _, opt := range app.Subcommands()[0].Options() {
+		info[opt.Names[0]] = opt.EnvVar
+	}
+	require.Equal(t, map[string]string{"--region": "MYAPP_DEPLOY_REGION", "--tags": "TAGS"}, info)
+}
+
+func TestEnvDelimiter(t *testing.T) {
+	var names []string
+	run := func() (string, error) {
+		app, out := testApp(t, "app")
+		app.Option("names", "").Env("NAMES").StringSliceVar(&names, nil)
+		app.Option("path", "").Env("APP_PATH").EnvDelimiter(":").StringSlice(nil)
+		app.Action = func(ctx Context) error {
+			return nil
+		}
+		err := app.Run([]string{"app"})
+		return out.String(), err
+	}
+
+	t.Setenv("NAMES", "O'Brien, Smith")
+	_, err := run()
+	require.NoError(t, err)
+	require.Equal(t, []string{"O'Brien", "Smith"}, names, "quotes should be kept without an explicit delimiter")
+
+	t.Setenv("APP_PATH", "'/bin")
+	out, err := run()
+	require.Error(t, err)
+	require.Contains(t, out, `error: invalid value for $APP_PATH: unterminated ' quote in "'/bin"`)
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -25,4 +26,40 @@ func (m MultiError) Error() string {
 	}
 
 	return strings.Join(errs, "\n")
-}
\ No newline at end of file
+}
+
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return NewMultiError(errs...)
+}
+
+// DefinitionError reports a mistake in the definition of a command, e.g. an invalid spec or a duplicate option name
+type DefinitionError struct {
+	// Path is the full path of the faulty command, e.g. "app deploy"
+	Path string
+	// Err is the underlying error
+	Err error
+}
+
+func (e *DefinitionError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err.Error())
+}
+
+// Unwrap returns the underlying error
+func (e *DefinitionError) Unwrap() error {
+	return e.Err
+}
+
+// SpecWarning reports a construct in a command spec which is valid but which most likely does not parse as intended
+type SpecWarning struct {
+	// Path is the full path of the command, e.g. "app deploy"
+	Path string
+	// Msg describes the problem
+	Msg string
+}
+
+func (w *SpecWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.Path, w.Msg)
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/duanqy/cli/internal/values"
+)
+
+/*
+ExternalCommands enables git-style external commands: when the args name no known command, e.g. app foo, an
+executable named after the command path, e.g. app-foo, or app-deploy-foo for app deploy foo, is looked up in dirs
+then in the PATH, and run with the remaining args.
+
+The external command shares the stdin, stdout and stderr of the app, receives the signals sent to it and its exit code
+becomes the one of the app. The values of the persistent options are passed in their env var, or in one derived from
+the app and option names, e.g. APP_VERBOSE.
+
+The external commands found are listed in the help of their parent command.
+*/
+func (a *App) ExternalCommands(dirs ...string) {
+	a.externals = true
+	a.pluginDirs = dirs
+}
+
+// ExitError is returned when an external command or a shell alias exits with a non zero code
+type ExitError struct {
+	// Path is the path of the executable
+	Path string
+	// Code is its exit code
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("%s exited with code %d", e.Path, e.Code)
+}
+
+// externalPrefix returns the prefix of the executables implementing the external sub commands of c, e.g. app-deploy-
+func (c *Cmd) externalPrefix() string {
+	return strings.Join(strings.Fields(c.fullPath()), "-") + "-"
+}
+
+// externalCommand returns the sub command of c implemented by an external executable with the given name, or nil
+func (c *Cmd) externalCommand(name string) *Cmd {
+	root := c.root()
+	if !root.externals || name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\`) {
+		return nil
+	}
+
+	file := c.externalPrefix() + name
+	path := ""
+	for _, dir := range root.pluginDirs {
+		if info, err := os.Stat(filepath.Join(dir, file)); err == nil && isExecutable(info) {
+			path = filepath.Join(dir, file)
+			break
+		}
+	}
+	if path == "" {
+		var err error
+		if path, err = exec.LookPath(file); err != nil {
+			return nil
+		}
+	}
+
+	return &Cmd{
+		ErrorHandling: c.ErrorHandling,
+		name:          name,
+		aliases:       []string{name},
+		parent:        c,
+		external:      path,
+		initialized:   true,
+	}
+}
+
+// externalCommands returns the names of the external sub commands of c which do not shadow a known command. The
+// dirs are only read the first time
+func (c *Cmd) externalCommands() []string {
+	root := c.root()
+	if !root.externals {
+		return nil
+	}
+	if !c.externalsListed {
+		c.externalNames, c.externalsListed = c.findExternalCommands(), true
+	}
+	return c.externalNames
+}
+
+func (c *Cmd) findExternalCommands() []string {
+	root := c.root()
+
+	prefix := c.externalPrefix()
+	seen := map[string]bool{}
+	var res []string
+	for _, dir := range append(append([]string{}, root.pluginDirs...), filepath.SplitList(os.Getenv("PATH"))...) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+				continue
+			}
+			name = strings.TrimPrefix(name, prefix)
+			if seen[name] || c.subcommand(strings.SplitN(name, "-", 2)[0]) != nil {
+				// the executables of the sub commands of a command, e.g. app-deploy-status, are listed in its help
+				continue
+			}
+			if info, err := entry.Info(); err != nil || !isExecutable(info) {
+				continue
+			}
+			seen[name] = true
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func isExecutable(info os.FileInfo) bool {
+	return !info.IsDir() && (runtime.GOOS == "windows" || info.Mode()&0o111 != 0)
+}
+
+// runExternal runs the executable implementing c with args
+func (c *Cmd) runExternal(args []string) error {
+	cmd := exec.Command(c.external, args...)
+	cmd.Env = append(os.Environ(), c.persistentEnv()...)
+	return c.runProcess(cmd)
+}
+
+// runProcess runs cmd, forwarding the stdio, the signals and the exit code
+func (c *Cmd) runProcess(cmd *exec.Cmd) error {
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, stdOut, stdErr
+	if err := cmd.Start(); err != nil {
+		err = fmt.Errorf("cannot run %s: %v", cmd.Path, err)
+		_, _ = fmt.Fprintf(stdErr, "error: %s\n", err.Error())
+		c.onError(err)
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for sig := range signals {
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+	defer func() {
+		signal.Stop(signals)
+		close(signals)
+	}()
+
+	err := cmd.Wait()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+	code := exitErr.ExitCode()
+	if code < 0 {
+		code = 1
+	}
+	err = &ExitError{Path: cmd.Path, Code: code}
+	switch c.ErrorHandling {
+	case flag.ExitOnError:
+		exiter(code)
+	case flag.PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
+// persistentEnv returns the values of the persistent options of the ancestors of c as env vars, e.g. APP_VERBOSE=true
+func (c *Cmd) persistentEnv() []string {
+	inherited, _ := c.inheritedOptions()
+	appName := c.root().name
+
+	var res []string
+	for _, opt := range inherited {
+		if dv, ok := opt.Value.(values.DefaultValued); ok && dv.IsDefault() {
+			continue
+		}
+		name := ""
+		switch vars := strings.Fields(opt.EnvVar); {
+		case len(vars) > 0:
+			name = vars[0]
+		case longOptName(opt) != "":
+			name = envVarName(appName, longOptName(opt))
+		default:
+			name = envVarName(appName, strings.TrimLeft(opt.Names[0], "-"))
+		}
+
+		value := opt.Value.String()
+		switch v := opt.Value.(type) {
+		case values.MultiValued:
+			value = joinSlice(opt.Value, envDelimiter(opt))
+		case *values.StringValue:
+			value = string(*v)
+		}
+		res = append(res, name+"="+value)
+	}
+	return res
+}
+
+// joinSlice joins the elements of a multi valued value with sep
+func joinSlice(v interface{}, sep string) string {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Slice {
+		return fmt.Sprint(v)
+	}
+	parts := make([]string, rv.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
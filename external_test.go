@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeExecutable(t *testing.T, path, script string) {
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755))
+}
+
+func TestExternalCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the external commands are shell scripts")
+	}
+	pluginDir, pathDir := t.TempDir(), t.TempDir()
+	writeExecutable(t, filepath.Join(pluginDir, "app-hello"), `echo "hello $* $APP_VERBOSE $REGION"; exit 3`)
+	writeExecutable(t, filepath.Join(pathDir, "app-deploy-status"), `echo "status $*"`)
+	writeExecutable(t, filepath.Join(pathDir, "app-deploy"), `echo "shadowed"`)
+	require.NoError(t, os.WriteFile(filepath.Join(pathDir, "app-notes"), nil, 0644))
+	t.Setenv("PATH", pathDir)
+
+	var out bytes.Buffer
+	prev := stdOut
+	stdOut = &out
+	t.Cleanup(func() {
+		stdOut = prev
+	})
+
+	newApp := func() (*App, func() string) {
+		app, help := testApp(t, "app")
+		app.ExternalCommands(pluginDir)
+		app.Option("v verbose", "").Persistent().Bool(false)
+		app.Option("region", "").Env("REGION").Persistent().String("us")
+		app.Command("deploy", "Deploy", func(cmd *Cmd) {
+			cmd.Action = func(ctx Context) error {
+				return nil
+			}
+		})
+		return app, help.String
+	}
+
+	t.Run("plugin dir", func(t *testing.T) {
+		out.Reset()
+		app, _ := newApp()
+		err := app.Run([]string{"app", "-v", "--region", "eu", "hello", "a", "b"})
+
+		exitErr, ok := err.(*ExitError)
+		require.True(t, ok, "expected an *ExitError, got %v", err)
+		require.Equal(t, 3, exitErr.Code)
+		require.Equal(t, "hello a b true eu\n", out.String())
+	})
+
+	t.Run("path", func(t *testing.T) {
+		out.Reset()
+		app, _ := newApp()
+
+		require.NoError(t, app.Run([]string{"app", "deploy", "status", "-x"}))
+		require.Equal(t, "status -x\n", out.String())
+	})
+
+	t.Run("zero values are not passed", func(t *testing.T) {
+		out.Reset()
+		app, _ := newApp()
+		_ = app.Run([]string{"app", "hello"})
+
+		require.Equal(t, "hello   us\n", out.String())
+	})
+
+	t.Run("help", func(t *testing.T) {
+		app, help := newApp()
+		require.NoError(t, app.Run([]string{"app", "-h"}))
+		require.Contains(t, help(), "External Commands:")
+		require.Regexp(t, `\n  hello +app-hello\n`, help())
+		require.NotContains(t, help(), "notes")
+		require.NotContains(t, help(), "deploy-status")
+
+		app, help = newApp()
+		require.NoError(t, app.Run([]string{"app", "deploy", "-h"}))
+		require.Regexp(t, `\n  status +app-deploy-status\n`, help())
+	})
+
+	t.Run("listed once", func(t *testing.T) {
+		app, _ := newApp()
+		require.Equal(t, []string{"hello"}, app.externalCommands())
+
+		writeExecutable(t, filepath.Join(pluginDir, "app-later"), "true")
+		require.Equal(t, []string{"hello"}, app.externalCommands(), "the dirs should only be read once")
+	})
+}
@@ -1,6 +1,10 @@
 module github.com/duanqy/cli
 
+go 1.18
+
 require (
 	github.com/davecgh/go-spew v1.1.1
 	github.com/stretchr/testify v1.3.0
 )
+
+require github.com/pmezard/go-difflib v1.0.0 // indirect
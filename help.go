@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/duanqy/cli/internal/container"
 	"github.com/duanqy/cli/internal/values"
+	"io"
 	"strings"
 	"text/tabwriter"
 )
@@ -39,7 +40,11 @@ func (c *Cmd) printHelp(longDesc bool) {
 		_, _ = fmt.Fprintf(stdErr, " %s", spec)
 	}
 
-	if len(c.commands) > 0 {
+	externals := c.externalCommands()
+	switch {
+	case len(c.commands) > 0 && c.DefaultCommand != "":
+		_, _ = fmt.Fprint(stdErr, " [COMMAND] [arg...]")
+	case len(c.commands) > 0 || len(externals) > 0:
 		_, _ = fmt.Fprint(stdErr, " COMMAND [arg...]")
 	}
 	_, _ = fmt.Fprint(stdErr, "\n\n")
@@ -69,35 +74,74 @@ func (c *Cmd) printHelp(longDesc bool) {
 	}
 
 	if len(c.options) > 0 {
-		_, _ = fmt.Fprint(w, "\t\nOptions:\t\n")
-		for _, opt := range c.options {
-			if opt.Hidden {
-				continue
+		c.printOptions(w, "Options", c.options)
+	}
+
+	if inherited, _ := c.inheritedOptions(); len(inherited) > 0 {
+		c.printOptions(w, "Global Options", inherited)
+	}
+
+	for _, group := range c.commandGroups() {
+		_, _ = fmt.Fprintf(w, "\t\n%s:\t\n", group.title())
+
+		for _, sub := range group.commands {
+			desc := joinStrings(sub.desc, sub.deprecationNote())
+			if sub.isAlias(c.DefaultCommand) {
+				desc = joinStrings(desc, "(default)")
 			}
-			var (
-				optNames = formatOptNamesForHelp(opt)
-				env      = formatEnvVarsForHelp(opt.EnvVar)
-				value    = formatValueForHelp(opt.Value)
-			)
-			printTabbedRow(w, optNames, joinStrings(opt.Desc, env, value))
+			_, _ = fmt.Fprintf(w, "  %s\t%s\n", formatCommandNamesForHelp(sub), desc)
 		}
 	}
 
-	if len(c.commands) > 0 {
-		_, _ = fmt.Fprint(w, "\t\nCommands:\t\n")
+	if c.parent == nil {
+		if names := c.aliasNames(); len(names) > 0 {
+			_, _ = fmt.Fprint(w, "\t\nAliases:\t\n")
+			for _, name := range names {
+				_, _ = fmt.Fprintf(w, "  %s\t%s\n", name, c.aliasDefs[name])
+			}
+		}
+	}
 
-		for _, c := range c.commands {
-			_, _ = fmt.Fprintf(w, "  %s\t%s\n", strings.Join(c.aliases, ", "), c.desc)
+	if len(externals) > 0 {
+		_, _ = fmt.Fprint(w, "\t\nExternal Commands:\t\n")
+		for _, name := range externals {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\n", name, c.externalPrefix()+name)
 		}
 	}
 
-	if len(c.commands) > 0 {
+	if len(c.commands) > 0 || len(externals) > 0 {
 		_, _ = fmt.Fprintf(w, "\t\nRun '%s COMMAND --help' for more information on a command.\n", path)
 	}
 
 	_ = w.Flush()
 }
 
+// visibleCommands returns the sub commands of c shown in its help
+func (c *Cmd) visibleCommands() []*Cmd {
+	var res []*Cmd
+	for _, sub := range c.commands {
+		if !sub.hidden {
+			res = append(res, sub)
+		}
+	}
+	return res
+}
+
+func (c *Cmd) printOptions(w io.Writer, title string, options []*container.Container) {
+	_, _ = fmt.Fprintf(w, "\t\n%s:\t\n", title)
+	for _, opt := range options {
+		if opt.Hidden {
+			continue
+		}
+		var (
+			optNames = formatOptNamesForHelp(opt)
+			env      = formatEnvVarsForHelp(opt.EnvVar)
+			value    = formatValueForHelp(opt.Value)
+		)
+		printTabbedRow(w, optNames, joinStrings(opt.Desc, env, value))
+	}
+}
+
 func formatOptNamesForHelp(o *container.Container) string {
 	short, long := "", ""
 
@@ -111,14 +155,22 @@ func formatOptNamesForHelp(o *container.Container) string {
 		}
 	}
 
+	value := ""
+	if placeholder := o.ValuePlaceholder(); placeholder != "" {
+		value = "=" + placeholder
+	}
+	if o.NumericShorthand {
+		value += ", -NUM"
+	}
+
 	switch {
 	case short != "" && long != "":
-		return fmt.Sprintf("%s, %s", short, long)
+		return fmt.Sprintf("%s, %s%s", short, long, value)
 	case short != "":
-		return short
+		return short + value
 	case long != "":
 		// 2 spaces instead of the short option (-x), one space for the comma (,) and one space for the after comma blank
-		return fmt.Sprintf("    %s", long)
+		return fmt.Sprintf("    %s%s", long, value)
 	default:
 		return ""
 	}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLongHelpMessage(t *testing.T) {
+	app, out := testApp(t, "app")
+	app.LongDesc = "Longer App Desc"
+	app.Spec = "[-o] ARG"
+	app.Option("o opt", "Option").Bool(false)
+	app.Argument("ARG", "Argument").String("")
+
+	require.NoError(t, app.Run([]string{"app", "-h"}))
+	requireGolden(t, "long-help-output.txt", out.String())
+}
+
+func TestHelpMessage(t *testing.T) {
+	app, out := testApp(t, "app")
+	app.desc = "App Desc"
+	app.Spec = "[-bdsuikqs] BOOL1 [STR1] INT3..."
+
+	app.Option("b bool1 u uuu", "Bool Option 1").Env("BOOL1").Bool(false)
+	app.Option("bool2", "Bool Option 2").Bool(true)
+	app.Option("d", "Bool Option 3").Env("BOOL3").Bool(false)
+
+	app.Option("s str1", "String Option 1").Env("STR1").String("")
+	app.Option("str2", "String Option 2").String("a value")
+	app.Option("v", "String Option 3").Env("STR3").String("")
+
+	app.Option("i int1", "").Env("INT1 ALIAS_INT1").Int(0)
+	app.Option("int2", "Int Option 2").Env("INT2").Int(1)
+	app.Option("k", "Int Option 3").Env("INT3").Int(0)
+
+	app.Option("x strs1", "Strings Option 1").Env("STRS1").StringSlice(nil)
+	app.Option("strs2", "Strings Option 2").Env("STRS2").StringSlice([]string{"value1", "value2"})
+	app.Option("z", "Strings Option 3").Env("STRS3").StringSlice(nil)
+
+	app.Option("q ints1", "Ints Option 1").Env("INTS1").IntSlice(nil)
+	app.Option("ints2", "Ints Option 2").Env("INTS2").IntSlice([]int{1, 2, 3})
+	app.Option("j", "Ints Option 3").Env("INTS3").IntSlice(nil)
+
+	app.Argument("BOOL1", "Bool Argument 1").Env("BOOL1").Bool(false)
+	app.Argument("BOOL2", "Bool Argument 2").Bool(true)
+	app.Argument("BOOL3", "Bool Argument 3").Env("BOOL3").Bool(false)
+
+	app.Argument("STR1", "String Argument 1").Env("STR1").String("")
+	app.Argument("STR2", "String Argument 2").Env("STR2").String("a value")
+	app.Argument("STR3", "String Argument 3").Env("STR3").String("")
+
+	app.Argument("INT1", "Int Argument 1").Env("INT1").Int(0)
+	app.Argument("INT2", "Int Argument 2").Env("INT2").Int(1)
+	app.Argument("INT3", "Int Argument 3").Env("INT3").Int(0)
+
+	app.Argument("STRS1", "Strings Argument 1").Env("STRS1").StringSlice(nil)
+	app.Argument("STRS2", "").Env("STRS2").StringSlice([]string{"value1", "value2"})
+	app.Argument("STRS3", "Strings Argument 3").Env("STRS3").StringSlice(nil)
+
+	app.Argument("INTS1", "Ints Argument 1").Env("INTS1").IntSlice(nil)
+	app.Argument("INTS2", "Ints Argument 2").Env("INTS2").IntSlice([]int{1, 2, 3})
+	app.Argument("INTS3", "Ints Argument 3").Env("INTS3").IntSlice(nil)
+
+	app.Command("command1", "command1 description", nil)
+	app.Command("command2", "command2 description", nil)
+	app.Command("command3", "command3 description", nil)
+
+	require.NoError(t, app.Run([]string{"app", "-h"}))
+	requireGolden(t, "help-output.txt", out.String())
+}
+
+func TestMultiLineDescInHelpMessage(t *testing.T) {
+	app, out := testApp(t, "app")
+	app.desc = "App Desc"
+	app.LongDesc = "Longer App Desc"
+	app.Spec = "[-o] ARG"
+
+	app.Option("o opt", "Option\ndoes something\nanother line").Env("XXX_TEST").String("default")
+	app.Option("f force", "Force\ndoes something\nanother line").Env("YYY_TEST").Bool(false)
+	app.Argument("ARG", "Argument\nDescription\nMultiple\nLines").String("")
+
+	require.NoError(t, app.Run([]string{"app", "-h"}))
+	requireGolden(t, "multi-line-desc-help-output.txt", out.String())
+}
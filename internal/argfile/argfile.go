@@ -0,0 +1,223 @@
+/*
+Package argfile expands response files, e.g. @args.txt, into the args they contain.
+
+A response file holds args separated by white spaces, with a shell-like syntax:
+
+  - single quotes preserve everything up to the next single quote
+  - double quotes preserve everything up to the next double quote, except \" and \\ which are unescaped
+  - outside of quotes, a backslash escapes the next char, and a backslash at the end of a line continues the
+    line
+  - a # at the start of an arg starts a comment which runs to the end of the line
+
+Response files can reference other response files, up to MaxDepth levels, which are read relatively to the
+directory of the referencing file. An arg starting with @@ stands for the arg starting with a single @, and so does
+an arg whose leading @ is quoted or escaped, e.g. '@x' or \@x.
+*/
+package argfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxDepth is the maximum nesting level of the response files
+const MaxDepth = 10
+
+// Source tells where an expanded arg comes from: a response file and a line, or the command line if File is empty
+type Source struct {
+	Arg  string
+	File string
+	Line int
+}
+
+// Error is a problem found while expanding a response file, located by its file and line if known
+type Error struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.File == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+/*
+Expand replaces the @file args by the args read from the files, and returns the resulting args with their sources.
+
+Expansion stops at the -- operator, the args after it being kept as is.
+*/
+func Expand(args []string) ([]string, []Source, error) {
+	e := &expander{}
+	for i, arg := range args {
+		if arg == "--" {
+			for _, arg := range args[i:] {
+				e.add(Source{Arg: arg})
+			}
+			break
+		}
+		if err := e.expand(Source{Arg: arg}, ".", 0); err != nil {
+			return nil, nil, err
+		}
+	}
+	return e.args, e.sources, nil
+}
+
+type expander struct {
+	args    []string
+	sources []Source
+}
+
+func (e *expander) add(src Source) {
+	e.args = append(e.args, src.Arg)
+	e.sources = append(e.sources, src)
+}
+
+// expand adds the arg src, reading it as a response file relative to dir if it starts with @
+func (e *expander) expand(src Source, dir string, depth int) error {
+	arg := src.Arg
+	switch {
+	case strings.HasPrefix(arg, "@@"):
+		src.Arg = arg[1:]
+		e.add(src)
+		return nil
+	case !strings.HasPrefix(arg, "@") || arg == "@":
+		e.add(src)
+		return nil
+	case depth == MaxDepth:
+		return &Error{File: src.File, Line: src.Line, Err: fmt.Errorf("response files nested more than %d levels deep", MaxDepth)}
+	}
+
+	path := arg[1:]
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return &Error{File: src.File, Line: src.Line, Err: fmt.Errorf("cannot read response file: %v", err)}
+	}
+	words, err := split(path, string(content))
+	if err != nil {
+		return err
+	}
+	for _, w := range words {
+		if w.quoted {
+			e.add(w.Source)
+			continue
+		}
+		if err := e.expand(w.Source, filepath.Dir(path), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fields splits s into args with the syntax of the response files, e.g. to read a command line from a config file
+func Fields(s string) ([]string, error) {
+	words, err := split("", s)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, len(words))
+	for i, w := range words {
+		res[i] = w.Arg
+	}
+	return res, nil
+}
+
+// word is an arg read from a response file
+type word struct {
+	Source
+	// quoted is true if the arg had quotes or started with an escaped char, in which case it is never expanded
+	quoted bool
+}
+
+// split reads the args in the content of the response file named file
+func split(file, content string) ([]word, error) {
+	var (
+		res    []word
+		cur    strings.Builder
+		inWord bool
+		quoted bool
+		line   = 1
+		start  = 1
+		pos    = 0
+		flush  = func() {
+			if inWord {
+				res = append(res, word{Source: Source{Arg: cur.String(), File: file, Line: start}, quoted: quoted})
+			}
+			cur.Reset()
+			inWord, quoted = false, false
+		}
+		begin = func() {
+			if !inWord {
+				inWord, start = true, line
+			}
+		}
+	)
+
+	for pos < len(content) {
+		c := content[pos]
+		switch {
+		case c == '\n':
+			flush()
+			line++
+			pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			flush()
+			pos++
+		case c == '#' && !inWord:
+			for pos < len(content) && content[pos] != '\n' {
+				pos++
+			}
+		case c == '\\' && strings.HasPrefix(content[pos+1:], "\n"):
+			line++
+			pos += 2
+		case c == '\\' && strings.HasPrefix(content[pos+1:], "\r\n"):
+			line++
+			pos += 3
+		case c == '\\':
+			if !inWord {
+				// an escaped @ is not a response file
+				quoted = true
+			}
+			begin()
+			if pos+1 < len(content) {
+				cur.WriteByte(content[pos+1])
+			}
+			pos += 2
+		case c == '\'' || c == '"':
+			begin()
+			quoted = true
+			from := line
+			pos++
+			for ; pos < len(content) && content[pos] != c; pos++ {
+				if content[pos] == '\n' {
+					line++
+				}
+				if c == '"' && content[pos] == '\\' && pos+1 < len(content) && (content[pos+1] == '"' || content[pos+1] == '\\') {
+					pos++
+				}
+				cur.WriteByte(content[pos])
+			}
+			if pos == len(content) {
+				return nil, &Error{File: file, Line: from, Err: fmt.Errorf("unterminated %c quote", c)}
+			}
+			pos++
+		default:
+			begin()
+			cur.WriteByte(c)
+			pos++
+		}
+	}
+	flush()
+	return res, nil
+}
@@ -0,0 +1,100 @@
+package argfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	cases := []struct {
+		content string
+		args    []string
+	}{
+		{"a b\tc\n d", []string{"a", "b", "c", "d"}},
+		{"# a comment\na # another one\nb#c", []string{"a", "b#c"}},
+		{`'a b' "c d" e\ f`, []string{"a b", "c d", "e f"}},
+		{`'a\b' "c\"d\\e\f"`, []string{`a\b`, `c"d\e\f`}},
+		{`a'b'"c"`, []string{"abc"}},
+		{"a\\\nb c \\\r\n d", []string{"ab", "c", "d"}},
+		{`'' ""`, []string{"", ""}},
+		{"", nil},
+	}
+	for _, cas := range cases {
+		t.Run(cas.content, func(t *testing.T) {
+			words, err := split("f", cas.content)
+			require.NoError(t, err)
+			var args []string
+			for _, w := range words {
+				args = append(args, w.Arg)
+			}
+			require.Equal(t, cas.args, args)
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	words, err := split("f", "a\n\n'b\nc' d\\\ne\nf")
+	require.NoError(t, err)
+	var lines []int
+	for _, w := range words {
+		lines = append(lines, w.Line)
+	}
+	require.Equal(t, []int{1, 3, 4, 6}, lines)
+}
+
+func TestSplitErrors(t *testing.T) {
+	_, err := split("args.txt", "a\nb 'c\nd")
+	require.EqualError(t, err, "args.txt:2: unterminated ' quote")
+}
+
+func TestFields(t *testing.T) {
+	fields, err := Fields(`checkout -b 'my branch'`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"checkout", "-b", "my branch"}, fields)
+
+	_, err = Fields(`log "oops`)
+	require.EqualError(t, err, "unterminated \" quote")
+}
+
+func TestExpand(t *testing.T) {
+	dir := t.TempDir()
+	write(t, dir, "args.txt", "a 'b c'\n@sub/more.txt\n@@at '@quoted' \\@escaped\n")
+	write(t, dir, "sub/more.txt", "d\n# e\nf")
+	args := []string{"x", "@" + filepath.Join(dir, "args.txt"), "@@y", "@", "--", "@z"}
+
+	res, sources, err := Expand(args)
+	require.NoError(t, err)
+	require.Equal(t, []string{"x", "a", "b c", "d", "f", "@at", "@quoted", "@escaped", "@y", "@", "--", "@z"}, res)
+
+	require.Len(t, sources, len(res))
+	require.Equal(t, Source{Arg: "x"}, sources[0])
+	require.Equal(t, Source{Arg: "b c", File: filepath.Join(dir, "args.txt"), Line: 1}, sources[2])
+	require.Equal(t, Source{Arg: "f", File: filepath.Join(dir, "sub/more.txt"), Line: 3}, sources[4])
+	require.Equal(t, Source{Arg: "@at", File: filepath.Join(dir, "args.txt"), Line: 3}, sources[5])
+}
+
+func TestExpandErrors(t *testing.T) {
+	dir := t.TempDir()
+	write(t, dir, "missing.txt", "a\n@nope.txt")
+	write(t, dir, "loop.txt", "@loop.txt")
+
+	_, _, err := Expand([]string{"@" + filepath.Join(dir, "missing.txt")})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), filepath.Join(dir, "missing.txt")+":2: cannot read response file")
+
+	_, _, err = Expand([]string{"@" + filepath.Join(dir, "loop.txt")})
+	require.EqualError(t, err, filepath.Join(dir, "loop.txt")+":1: response files nested more than 10 levels deep")
+
+	_, _, err = Expand([]string{"@" + filepath.Join(dir, "none.txt")})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot read response file")
+}
+
+func write(t *testing.T, dir, name, content string) {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
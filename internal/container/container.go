@@ -2,17 +2,38 @@ package container
 
 import (
 	"flag"
+
+	"github.com/duanqy/cli/internal/values"
 )
 
 // Container holds an option or an arg data
 type Container struct {
-	Name            string
-	Desc            string
-	EnvVar          string
-	Names           []string
-	Hidden          bool
-	ValueSetFromEnv bool
-	ValueSetByUser  *bool
-	Value           flag.Value
-	Default         interface{}
+	Name         string
+	Desc         string
+	EnvVar       string
+	NoEnv        bool
+	Delimiter    string
+	EnvDelimiter string
+	Names        []string
+	Placeholder  string
+	Hidden       bool
+	Persistent   bool
+	// NumericShorthand allows setting the (numeric) option with a dash followed by its value, e.g. -5
+	NumericShorthand bool
+	ValueSetFromEnv  bool
+	ValueSetByUser   *bool
+	Value            flag.Value
+	Default          interface{}
+}
+
+// ValuePlaceholder returns the placeholder of the value, either the one given in the spec, e.g. -f=<file>,
+// or one derived from the value type, e.g. <int>. It is empty for the bool values, which take none
+func (c *Container) ValuePlaceholder() string {
+	if values.IsBool(c.Value) {
+		return ""
+	}
+	if c.Placeholder != "" {
+		return c.Placeholder
+	}
+	return values.Placeholder(c.Value)
 }
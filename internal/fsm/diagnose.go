@@ -0,0 +1,379 @@
+package fsm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/duanqy/cli/internal/matcher"
+	"github.com/duanqy/cli/internal/values"
+)
+
+// UsageError is returned by Parse when the args do not match the spec
+type UsageError struct {
+	// Msg describes the problem, e.g. missing argument DST
+	Msg string
+	// Args are the parsed args
+	Args []string
+	// Pos is the index in Args of the offending arg, or len(Args) if some input is missing
+	Pos int
+	// Unknown is the name of the unknown option or the unexpected argument, if that is the problem
+	Unknown string
+	// Unexpected is true if the arg at Pos is not accepted anywhere by the spec, i.e. an unknown option or an extra
+	// argument, even after the -- operator
+	Unexpected bool
+}
+
+func (e *UsageError) Error() string {
+	return e.Msg
+}
+
+// failure describes where the navigation in the FSM went the furthest before failing
+type failure struct {
+	// states are the states from which no transition could consume the remaining args
+	states []*State
+	rem    []string
+	reject bool
+	path   *step
+}
+
+// fail records a failed navigation from the state s if it went at least as far as the previous failures
+func (m *matching) fail(s *State, args []string, reject bool, prev *step) {
+	switch {
+	case m.furthest == nil || m.further(args, m.furthest.rem):
+		m.furthest = &failure{rem: args, reject: reject, path: prev}
+	case m.further(m.furthest.rem, args):
+		return
+	}
+	m.furthest.states = append(m.furthest.states, s)
+}
+
+/*
+further checks whether less input remains in a than in b.
+
+Options can be consumed out of order or from the middle of a group, e.g. -abc, so when the same number of args
+remain, the one starting later in the args wins, then the shortest one.
+*/
+func (m *matching) further(a, b []string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	if pa, pb := position(m.args, a), position(m.args, b); pa != pb {
+		return pa > pb
+	}
+	return size(a) < size(b)
+}
+
+func size(args []string) int {
+	res := 0
+	for _, arg := range args {
+		res += len(arg)
+	}
+	return res
+}
+
+// diagnose explains why the args were rejected by the FSM starting with the state start
+func (m *matching) diagnose(start *State) *UsageError {
+	args, f := m.args, m.furthest
+	if f == nil {
+		return &UsageError{Msg: "incorrect usage", Args: args, Pos: len(args)}
+	}
+
+	err := &UsageError{Args: args, Pos: position(args, f.rem)}
+	if len(f.rem) == 0 {
+		err.Msg = f.missing()
+		return err
+	}
+
+	tok := f.rem[0]
+	known := options(start)
+	if f.reject || !isOption(tok) {
+		err.Msg = fmt.Sprintf("unexpected argument '%s'%s", tok, f.after(args, known))
+		err.Unexpected = true
+		if !f.reject {
+			err.Unknown = tok
+		}
+		if lits := f.expected(matcher.IsLiteral); len(lits) > 0 {
+			err.Msg += fmt.Sprintf(", expected %s", OrList(lits))
+		}
+		return err
+	}
+
+	name, con := f.option(tok, known)
+	switch {
+	case con == nil:
+		err.Msg = fmt.Sprintf("unknown option %s", name)
+		err.Unknown = name
+		err.Unexpected = true
+	case f.expects(con):
+		err.Msg = fmt.Sprintf("missing value for option %s", withPlaceholder(name, con))
+	case f.given(con) && !isMultiValued(con):
+		err.Msg = fmt.Sprintf("option %s given twice", name)
+	default:
+		if other := f.conflict(start, con); other != nil {
+			err.Msg = fmt.Sprintf("%s cannot be combined with %s", name, typedName(args, other, known))
+		} else {
+			err.Msg = fmt.Sprintf("unexpected option %s%s", name, f.after(args, known))
+		}
+	}
+	return err
+}
+
+// missing describes what the failed states were expecting when all the args were consumed
+func (f *failure) missing() string {
+	if args := f.expected(matcher.IsArg); len(args) > 0 {
+		return fmt.Sprintf("missing argument %s", OrList(args))
+	}
+	if lits := f.expected(matcher.IsLiteral); len(lits) > 0 {
+		return fmt.Sprintf("missing keyword %s", OrList(lits))
+	}
+	var opts []string
+	for _, s := range f.states {
+		for _, tr := range s.Transitions {
+			for _, con := range matcher.Containers(tr.Matcher) {
+				if len(con.Names) == 0 {
+					continue
+				}
+				if name := withPlaceholder(con.Names[0], con); !contains(opts, name) {
+					opts = append(opts, name)
+				}
+			}
+		}
+	}
+	if len(opts) > 0 {
+		return fmt.Sprintf("missing option %s", OrList(opts))
+	}
+	return "incorrect usage"
+}
+
+// expected returns the names of the matchers accepted by the failed states and selected by the filter
+func (f *failure) expected(filter func(matcher.Matcher) bool) []string {
+	var res []string
+	for _, s := range f.states {
+		for _, tr := range s.Transitions {
+			if name := fmt.Sprint(tr.Matcher); filter(tr.Matcher) && !contains(res, name) {
+				res = append(res, name)
+			}
+		}
+	}
+	return res
+}
+
+// after names the last element matched before the failure, if any. A group of options is named after the option
+// it matched, as typed in args, and left out if it matched several ones
+func (f *failure) after(args []string, known map[string]*container.Container) string {
+	for p := f.path; p != nil; p = p.prev {
+		switch {
+		case matcher.IsOptsEnd(p.matcher):
+			continue
+		case matcher.IsOptions(p.matcher):
+			if len(p.pc.Opts) != 1 {
+				return ""
+			}
+			for con := range p.pc.Opts {
+				return " after " + typedName(args, con, known)
+			}
+		}
+		return fmt.Sprintf(" after %v", p.matcher)
+	}
+	return ""
+}
+
+// option finds the first option of tok, which may be a group of short options, which could not be matched
+func (f *failure) option(tok string, known map[string]*container.Container) (string, *container.Container) {
+	if strings.HasPrefix(tok, "--") {
+		name := strings.SplitN(tok, "=", 2)[0]
+		return name, known[name]
+	}
+	for i := 1; i < len(tok) && tok[i] != '='; i++ {
+		name := "-" + tok[i:i+1]
+		con := known[name]
+		if con == nil || !values.IsBool(con.Value) || !f.given(con) && !f.expects(con) {
+			return name, con
+		}
+	}
+	return tok[:2], known[tok[:2]]
+}
+
+// expects checks whether one of the failed states has a transition for con
+func (f *failure) expects(con *container.Container) bool {
+	for _, s := range f.states {
+		for _, tr := range s.Transitions {
+			for _, c := range matcher.Containers(tr.Matcher) {
+				if c == con {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// given checks whether con was matched before the failure
+func (f *failure) given(con *container.Container) bool {
+	for p := f.path; p != nil; p = p.prev {
+		if _, ok := p.pc.Opts[con]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// conflict returns an option matched before the failure which is an alternative to con in the spec
+func (f *failure) conflict(start *State, con *container.Container) *container.Container {
+	for p := f.path; p != nil; p = p.prev {
+		for other := range p.pc.Opts {
+			if other != con && alternatives(start, con, other) {
+				return other
+			}
+		}
+	}
+	return nil
+}
+
+// alternatives checks whether a and b are matched by different transitions of the same state, e.g. -a | -b
+func alternatives(start *State, a, b *container.Container) bool {
+	for _, s := range reachable(start) {
+		var foundA, foundB bool
+		for _, tr := range s.Transitions {
+			cons := matcher.Containers(tr.Matcher)
+			if len(cons) != 1 {
+				continue
+			}
+			foundA = foundA || cons[0] == a
+			foundB = foundB || cons[0] == b
+		}
+		if foundA && foundB {
+			return true
+		}
+	}
+	return false
+}
+
+func reachable(start *State) []*State {
+	var res []*State
+	visited := map[*State]bool{}
+	var visit func(s *State)
+	visit = func(s *State) {
+		if visited[s] {
+			return
+		}
+		visited[s] = true
+		res = append(res, s)
+		for _, tr := range s.Transitions {
+			visit(tr.Next)
+		}
+	}
+	visit(start)
+	return res
+}
+
+// options indexes by name the options used in the FSM
+func options(start *State) map[string]*container.Container {
+	res := map[string]*container.Container{}
+	for _, s := range reachable(start) {
+		for _, tr := range s.Transitions {
+			for _, con := range matcher.Containers(tr.Matcher) {
+				for _, name := range con.Names {
+					res[name] = con
+				}
+			}
+		}
+	}
+	return res
+}
+
+// typedName returns the name the user typed for the option con, or its first name
+func typedName(args []string, con *container.Container, known map[string]*container.Container) string {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !isOption(arg) {
+			continue
+		}
+		if strings.HasPrefix(arg, "--") {
+			if name := strings.SplitN(arg, "=", 2)[0]; known[name] == con {
+				return name
+			}
+			continue
+		}
+		for i := 1; i < len(arg) && arg[i] != '='; i++ {
+			if name := "-" + arg[i:i+1]; known[name] == con {
+				return name
+			}
+		}
+	}
+	return con.Names[0]
+}
+
+/*
+position returns the index in args of the first of the remaining args rem.
+
+The matchers can remove options from the middle of the args or rewrite a group of short options, e.g. -abc into -ac,
+but they preserve the order of the args, so both slices are aligned from the end.
+*/
+func position(args, rem []string) int {
+	i, j := len(args)-1, len(rem)-1
+	for ; i >= 0 && j >= 0; i-- {
+		if args[i] == rem[j] || shortened(args[i], rem[j]) {
+			j--
+		}
+	}
+	if j >= 0 {
+		return 0
+	}
+	return i + 1
+}
+
+// shortened checks whether the group of short options rem is what remains of arg after some options were matched
+func shortened(arg, rem string) bool {
+	if !isOption(arg) || !isOption(rem) || strings.HasPrefix(arg, "--") || strings.HasPrefix(rem, "--") {
+		return false
+	}
+	i := 1
+	for j := 1; j < len(rem); j++ {
+		for i < len(arg) && arg[i] != rem[j] {
+			i++
+		}
+		if i == len(arg) {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// withPlaceholder appends the placeholder of the value of the option con to its name, e.g. --output=<file>
+func withPlaceholder(name string, con *container.Container) string {
+	if placeholder := con.ValuePlaceholder(); placeholder != "" {
+		return name + "=" + placeholder
+	}
+	return name
+}
+
+func isOption(arg string) bool {
+	return len(arg) > 1 && strings.HasPrefix(arg, "-")
+}
+
+func isMultiValued(con *container.Container) bool {
+	_, ok := con.Value.(values.MultiValued)
+	return ok
+}
+
+func contains(strs []string, s string) bool {
+	for _, x := range strs {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// OrList joins strs as an alternative: "a, b or c"
+func OrList(strs []string) string {
+	if len(strs) == 1 {
+		return strs[0]
+	}
+	return strings.Join(strs[:len(strs)-1], ", ") + " or " + strs[len(strs)-1]
+}
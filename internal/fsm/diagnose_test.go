@@ -0,0 +1,99 @@
+package fsm_test
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/duanqy/cli/internal/fsm"
+	"github.com/duanqy/cli/internal/lexer"
+	"github.com/duanqy/cli/internal/parser"
+	"github.com/duanqy/cli/internal/values"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiagnostics(t *testing.T) {
+	params := parser.Params{
+		OptionsIdx:  map[string]*container.Container{},
+		ArgsIdx:     map[string]*container.Container{},
+		LiteralsIdx: map[string]*container.Container{},
+	}
+	addOpt := func(v flag.Value, names ...string) {
+		con := &container.Container{Name: names[0], Names: names, Value: v}
+		params.Options = append(params.Options, con)
+		for _, n := range names {
+			params.OptionsIdx[n] = con
+		}
+	}
+	addOpt(values.NewBool(new(bool), false), "-f", "--force")
+	addOpt(values.NewBool(new(bool), false), "-d")
+	addOpt(values.NewBool(new(bool), false), "--rm")
+	addOpt(values.NewString(new(string), ""), "-o")
+	addOpt(values.NewStrings(new([]string), nil), "-I")
+	for _, name := range []string{"SRC", "DST", "IMAGE", "NAME"} {
+		con := &container.Container{Name: name, Value: values.NewString(new(string), "")}
+		params.Args = append(params.Args, con)
+		params.ArgsIdx[name] = con
+	}
+
+	cases := []struct {
+		spec string
+		args []string
+		msg  string
+		pos  int
+	}{
+		{"SRC DST", []string{"a"}, "missing argument DST", 1},
+		{"SRC", []string{"a", "foo"}, "unexpected argument 'foo' after SRC", 1},
+		{"SRC", nil, "missing argument SRC", 0},
+		{"-f SRC", nil, "missing option -f", 0},
+		{"[-f] SRC", []string{"-f", "-f", "a"}, "option -f given twice", 1},
+		{"[-f] SRC", []string{"--force", "a", "--force"}, "option --force given twice", 2},
+		{"[-I...] SRC", []string{"-I", "x", "a", "-I", "y"}, "unexpected option -I after SRC", 3},
+		{"[-d | --rm] IMAGE", []string{"-d", "--rm", "img"}, "--rm cannot be combined with -d", 1},
+		{"[-d | --rm] IMAGE", []string{"--rm", "-d", "img"}, "-d cannot be combined with --rm", 1},
+		{"[-d | -f] IMAGE", []string{"-df", "img"}, "-f cannot be combined with -d", 0},
+		{"SRC", []string{"-x"}, "unknown option -x", 0},
+		{"[-f] SRC", []string{"-fx", "a"}, "unknown option -x", 0},
+		{"SRC", []string{"a", "--nope=1"}, "unknown option --nope", 1},
+		{"[-o] SRC", []string{"-o"}, "missing value for option -o=<string>", 0},
+		{"-o SRC", nil, "missing option -o=<string>", 0},
+		{"(start | stop) NAME", []string{"bogus", "x"}, "unexpected argument 'bogus', expected start or stop", 0},
+		{"(start | stop) NAME", []string{"start"}, "missing argument NAME", 1},
+		{"service (start | stop) NAME", []string{"service"}, "missing keyword start or stop", 1},
+		{"SRC [-- DST]", []string{"a", "--", "b", "c"}, "unexpected argument 'c' after DST", 3},
+		{"[OPTIONS]", []string{"--force", "x"}, "unexpected argument 'x' after --force", 1},
+		{"[OPTIONS]", []string{"-f", "-o", "v", "x"}, "unexpected argument 'x'", 3},
+		{"[OPTIONS] SRC", []string{"-I", "x", "a", "-o", "v"}, "unexpected option -o after SRC", 3},
+		// the placeholder is stored on the container: keep last
+		{"[-o=<file>] SRC", []string{"-o"}, "missing value for option -o=<file>", 0},
+	}
+
+	for _, cas := range cases {
+		t.Run(fmt.Sprintf("%s %v", cas.spec, cas.args), func(t *testing.T) {
+			params.Spec = cas.spec
+			tokens, err := lexer.Tokenize(cas.spec)
+			require.NoError(t, err)
+			s, err := parser.Parse(tokens, params)
+			require.NoError(t, err)
+
+			err = s.Parse(cas.args)
+			require.Error(t, err)
+			uerr, ok := err.(*fsm.UsageError)
+			require.Truef(t, ok, "expected a *UsageError, got %T", err)
+			require.Equal(t, cas.msg, uerr.Msg)
+			require.Equal(t, cas.pos, uerr.Pos)
+			require.Equal(t, cas.args, uerr.Args)
+			require.Equal(t, strings.HasPrefix(cas.msg, "unknown option ") || strings.HasPrefix(cas.msg, "unexpected argument "), uerr.Unexpected)
+			switch {
+			case strings.HasPrefix(cas.msg, "unknown option "):
+				require.Equal(t, strings.TrimPrefix(cas.msg, "unknown option "), uerr.Unknown)
+			case strings.HasPrefix(cas.msg, "unexpected argument ") && !strings.Contains(cas.spec, "--"):
+				require.Equal(t, cas.args[cas.pos], uerr.Unknown)
+			default:
+				require.Empty(t, uerr.Unknown)
+			}
+		})
+	}
+}
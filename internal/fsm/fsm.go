@@ -3,8 +3,6 @@ package fsm
 import (
 	"sort"
 
-	"fmt"
-
 	"github.com/duanqy/cli/internal/container"
 	"github.com/duanqy/cli/internal/matcher"
 	"github.com/duanqy/cli/internal/values"
@@ -50,7 +48,8 @@ func (s *State) T(matcher matcher.Matcher, next *State) *State {
 	return next
 }
 
-// Prepare simplifies the FSM and sorts the transitions according to their priorities
+// Prepare simplifies the FSM and sorts the transitions according to their priorities, transitions with the same
+// priority keeping their relative order
 func (s *State) Prepare() {
 	simplify(s, s, map[*State]bool{})
 	sortTransitions(s, map[*State]bool{})
@@ -62,7 +61,7 @@ func sortTransitions(s *State, visited map[*State]bool) {
 	}
 	visited[s] = true
 
-	sort.Sort(s.Transitions)
+	sort.Stable(s.Transitions)
 
 	for _, tr := range s.Transitions {
 		sortTransitions(tr.Next, visited)
@@ -116,12 +115,12 @@ func (s *State) has(tr *Transition) bool {
 	return false
 }
 
-// Parse tries to navigate into the FSM according to the provided args
+// Parse tries to navigate into the FSM according to the provided args.
+// When the args are rejected, the returned error is a *UsageError describing the first problem found
 func (s *State) Parse(args []string) error {
-	pc := matcher.NewParseContext()
-	ok := s.apply(args, pc)
+	pc, m, ok := s.match(args)
 	if !ok {
-		return fmt.Errorf("incorrect usage")
+		return m.diagnose(s)
 	}
 
 	if err := fillContainers(pc.Opts); err != nil {
@@ -131,13 +130,62 @@ func (s *State) Parse(args []string) error {
 	return fillContainers(pc.Args)
 }
 
+// Match tries to navigate into the FSM according to the provided args, returning the matched values without
+// filling the containers
+func (s *State) Match(args []string) (matcher.ParseContext, bool) {
+	pc, _, ok := s.match(args)
+	return pc, ok
+}
+
+// Requires checks whether the container con has to be matched to reach a terminal state, e.g. SRC in "[-f] SRC"
+func (s *State) Requires(con *container.Container) bool {
+	visited := map[*State]bool{}
+	var reaches func(s *State) bool
+	reaches = func(s *State) bool {
+		if s.Terminal {
+			return true
+		}
+		if visited[s] {
+			return false
+		}
+		visited[s] = true
+		for _, tr := range s.Transitions {
+			if cons := matcher.Containers(tr.Matcher); len(cons) == 1 && cons[0] == con {
+				continue
+			}
+			if reaches(tr.Next) {
+				return true
+			}
+		}
+		return false
+	}
+	return !reaches(s)
+}
+
+func (s *State) match(args []string) (matcher.ParseContext, *matching, bool) {
+	pc := matcher.NewParseContext()
+	m := &matching{args: args, failed: map[memoKey]bool{}}
+	path, ok := s.apply(args, false, m, nil)
+	if !ok {
+		return pc, m, false
+	}
+	var steps []*step
+	for ; path != nil; path = path.prev {
+		steps = append(steps, path)
+	}
+	for i := len(steps) - 1; i >= 0; i-- {
+		pc.Merge(steps[i].pc)
+	}
+	return pc, m, true
+}
+
 func fillContainers(containers map[*container.Container][]string) error {
 	for con, vs := range containers {
 		if multiValued, ok := con.Value.(values.MultiValued); ok {
 			multiValued.Clear()
 		}
 		for _, v := range vs {
-			if err := con.Value.Set(v); err != nil {
+			if err := setValue(con, v); err != nil {
 				return err
 			}
 		}
@@ -150,18 +198,93 @@ func fillContainers(containers map[*container.Container][]string) error {
 	return nil
 }
 
-func (s *State) apply(args []string, pc matcher.ParseContext) bool {
-	if s.Terminal && len(args) == 0 {
-		return true
+func setValue(con *container.Container, v string) error {
+	if _, ok := con.Value.(values.MultiValued); !ok || con.Delimiter == "" {
+		return con.Value.Set(v)
+	}
+	vs, err := values.Split(v, con.Delimiter)
+	if err != nil {
+		return err
 	}
+	for _, v := range vs {
+		if err := con.Value.Set(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+memoKey identifies a call to apply: the outcome only depends on the state, the remaining args and whether options are
+still accepted.
 
+The remaining args are identified by the address of their first element and their length: matchers never modify
+the args they are given, they either reslice them or return a fresh copy. Two equal but distinct slices get different
+keys, which only costs a cache miss.
+*/
+type memoKey struct {
+	s      *State
+	first  *string
+	n      int
+	reject bool
+}
+
+func newMemoKey(s *State, args []string, reject bool) memoKey {
+	k := memoKey{s: s, n: len(args), reject: reject}
 	if len(args) > 0 {
-		arg := args[0]
+		k.first = &args[0]
+	}
+	return k
+}
+
+// step is a transition taken while navigating the FSM, linked to the step before it
+type step struct {
+	matcher matcher.Matcher
+	pc      matcher.ParseContext
+	prev    *step
+	depth   int
+}
+
+// matching holds the state of a navigation in the FSM
+type matching struct {
+	args []string
+	// failed memoizes the calls to apply which failed: without it, specs like [SRC...] [DST...] would explore
+	// an exponential number of paths before giving up
+	failed map[memoKey]bool
+	// furthest is where the navigation went the furthest before failing, to explain the failure
+	furthest *failure
+	// trace, if set, logs the transitions tried
+	trace *tracer
+}
+
+/*
+apply looks for a path from s to a terminal state consuming all of args.
+prev is the path which led to s.
+
+On success, it returns the complete path, the last step first.
+*/
+func (s *State) apply(args []string, reject bool, m *matching, prev *step) (*step, bool) {
+	if !reject && len(args) > 0 && args[0] == "--" {
+		reject = true
+		args = args[1:]
+	}
 
-		if !pc.RejectOptions && arg == "--" {
-			pc.RejectOptions = true
-			args = args[1:]
+	if s.Terminal && len(args) == 0 {
+		if m.trace != nil {
+			m.trace.log(prev, "%s: accepted", m.trace.name(s))
 		}
+		return prev, true
+	}
+
+	key := newMemoKey(s, args, reject)
+	if m.failed[key] {
+		if m.trace != nil {
+			m.trace.log(prev, "%s %q: already failed", m.trace.name(s), args)
+		}
+		return nil, false
+	}
+	if m.trace != nil {
+		m.trace.log(prev, "%s %q", m.trace.name(s), args)
 	}
 
 	type match struct {
@@ -173,18 +296,27 @@ func (s *State) apply(args []string, pc matcher.ParseContext) bool {
 	var matches []*match
 	for _, tr := range s.Transitions {
 		fresh := matcher.NewParseContext()
-		fresh.RejectOptions = pc.RejectOptions
-		if ok, rem := tr.Matcher.Match(args, &fresh); ok {
+		fresh.RejectOptions = reject
+		ok, rem := tr.Matcher.Match(args, &fresh)
+		if ok {
 			matches = append(matches, &match{tr, rem, fresh})
 		}
+		if m.trace != nil {
+			m.trace.transition(prev, tr, ok)
+		}
 	}
 
-	for _, m := range matches {
-		if ok := m.tr.Next.apply(m.rem, m.pc); ok {
-			pc.Merge(m.pc)
-			return true
+	for _, mt := range matches {
+		next := &step{matcher: mt.tr.Matcher, pc: mt.pc, prev: prev}
+		if prev != nil {
+			next.depth = prev.depth + 1
+		}
+		if path, ok := mt.tr.Next.apply(mt.rem, mt.pc.RejectOptions, m, next); ok {
+			return path, true
 		}
 	}
 
-	return false
+	m.failed[key] = true
+	m.fail(s, args, reject, prev)
+	return nil, false
 }
@@ -0,0 +1,87 @@
+package fsm_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/duanqy/cli/internal/fsm"
+	"github.com/duanqy/cli/internal/lexer"
+	"github.com/duanqy/cli/internal/parser"
+	"github.com/duanqy/cli/internal/values"
+	"github.com/stretchr/testify/require"
+)
+
+func mkBacktrackingFsm(t testing.TB, spec string) *fsm.State {
+	var (
+		optA = &container.Container{Name: "a", Names: []string{"-a"}, Value: values.NewBool(new(bool), false)}
+		optB = &container.Container{Name: "b", Names: []string{"-b"}, Value: values.NewBool(new(bool), false)}
+		src  = &container.Container{Name: "SRC", Value: values.NewStrings(new([]string), nil)}
+		dst  = &container.Container{Name: "DST", Value: values.NewStrings(new([]string), nil)}
+	)
+	tokens, err := lexer.Tokenize(spec)
+	require.NoError(t, err)
+	s, err := parser.Parse(tokens, parser.Params{
+		Spec:       spec,
+		Options:    []*container.Container{optA, optB},
+		OptionsIdx: map[string]*container.Container{"-a": optA, "-b": optB},
+		Args:       []*container.Container{src, dst},
+		ArgsIdx:    map[string]*container.Container{"SRC": src, "DST": dst},
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func files(n int, last ...string) []string {
+	res := make([]string, 0, n+len(last))
+	for i := 0; i < n; i++ {
+		res = append(res, fmt.Sprintf("file%d", i))
+	}
+	return append(res, last...)
+}
+
+func TestMatchDoesNotBacktrackExponentially(t *testing.T) {
+	s := mkBacktrackingFsm(t, "[-a] [-b] [SRC...] [DST...]")
+
+	_, ok := s.Match(files(500, "-x"))
+	require.False(t, ok)
+
+	pc, ok := s.Match(files(500, "-a"))
+	require.False(t, ok, "-a is not allowed after the arguments")
+
+	pc, ok = s.Match(append([]string{"-b"}, files(500)...))
+	require.True(t, ok)
+	require.Len(t, pc.Opts, 1)
+	for con, vs := range pc.Args {
+		require.Equal(t, "SRC", con.Name)
+		require.Len(t, vs, 500)
+	}
+
+	s = mkBacktrackingFsm(t, "(SRC | DST)... -a")
+	_, ok = s.Match(files(100, "-x"))
+	require.False(t, ok)
+}
+
+func benchmarkMatch(b *testing.B, spec string, args []string) {
+	s := mkBacktrackingFsm(b, spec)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Match(args)
+	}
+}
+
+const (
+	optionalsSpec = "[-a] [-b] [SRC...] [DST...]"
+	choicesSpec   = "(SRC | DST)... -a"
+)
+
+func BenchmarkMatch100(b *testing.B)  { benchmarkMatch(b, optionalsSpec, files(100)) }
+func BenchmarkMatch1000(b *testing.B) { benchmarkMatch(b, optionalsSpec, files(1000)) }
+
+func BenchmarkMatchFailure100(b *testing.B)  { benchmarkMatch(b, optionalsSpec, files(100, "-x")) }
+func BenchmarkMatchFailure1000(b *testing.B) { benchmarkMatch(b, optionalsSpec, files(1000, "-x")) }
+
+func BenchmarkMatchChoicesFailure20(b *testing.B) { benchmarkMatch(b, choicesSpec, files(20, "-x")) }
+func BenchmarkMatchChoicesFailure1000(b *testing.B) {
+	benchmarkMatch(b, choicesSpec, files(1000, "-x"))
+}
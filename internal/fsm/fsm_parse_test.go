@@ -1,6 +1,7 @@
 package fsm_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/duanqy/cli/internal/container"
@@ -27,6 +28,15 @@ func TestParseNoErrorWhenApplyReturnsTrue(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestParseAcceptsTrailingOptsEnd(t *testing.T) {
+	s := fsm.NewState()
+	s.Terminal = true
+
+	err := s.Parse([]string{"--"})
+
+	require.NoError(t, err)
+}
+
 func TestParseFillsContainers(t *testing.T) {
 	var (
 		boolSetByUser    = false
@@ -72,3 +82,93 @@ func TestParseFillsContainers(t *testing.T) {
 	require.True(t, stringsSetByUser)
 	require.Equal(t, stringsVar, []string{"new", "value"})
 }
+
+func TestParseSplitsDelimitedValues(t *testing.T) {
+	var (
+		strsVar []string
+		strVar  string
+		strsCon = &container.Container{
+			Value:     values.NewStrings(&strsVar, nil),
+			Delimiter: ",",
+		}
+		strCon = &container.Container{
+			Value:     values.NewString(&strVar, ""),
+			Delimiter: ",",
+		}
+	)
+	matchers := map[string]matcher.Matcher{
+		"^": fsmtest.TestMatcher{
+			TestPriority: 2,
+			MatchFunc: func(args []string, c *matcher.ParseContext) (bool, []string) {
+				c.Opts[strsCon] = []string{"a,b", `"c,d"`}
+				c.Opts[strCon] = []string{"x,y"}
+				return true, nil
+			},
+		},
+	}
+	s := fsmtest.NewFsm(`
+		S1 ^ (S2)
+	`, matchers)
+
+	s.Prepare()
+
+	err := s.Parse([]string{"something"})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c,d"}, strsVar)
+	require.Equal(t, "x,y", strVar, "single valued containers should not be split")
+}
+
+func TestRequires(t *testing.T) {
+	var (
+		a = &container.Container{Name: "a"}
+		b = &container.Container{Name: "b"}
+		c = &container.Container{Name: "c"}
+	)
+	matchers := map[string]matcher.Matcher{
+		"a": matcher.NewArg(a),
+		"b": matcher.NewArg(b),
+		"c": matcher.NewArg(c),
+	}
+
+	s := fsmtest.NewFsm(`
+		S1 a S2
+		S2 b (S3)
+		S2 c S4
+		S4 b (S3)
+	`, matchers)
+
+	require.True(t, s.Requires(a))
+	require.True(t, s.Requires(b))
+	require.False(t, s.Requires(c))
+}
+
+func TestTrace(t *testing.T) {
+	var (
+		a = &container.Container{Name: "A"}
+		b = &container.Container{Name: "B"}
+	)
+	s := fsmtest.NewFsm(`
+		S1 A S2
+		S1 B (S3)
+		S2 B (S3)
+	`, map[string]matcher.Matcher{
+		"A": matcher.NewArg(a),
+		"B": matcher.NewArg(b),
+	})
+
+	var out strings.Builder
+	err := s.Trace([]string{"x"}, &out)
+	require.NoError(t, err)
+	require.Equal(t, `S1 ["x"]
+  A -> S2: matched
+  B -> S3: matched
+  S2 []
+    B -> S3: no match
+  S3: accepted
+`, out.String())
+
+	out.Reset()
+	err = s.Trace([]string{"x", "y", "z"}, &out)
+	require.EqualError(t, err, "unexpected argument 'z' after B")
+}
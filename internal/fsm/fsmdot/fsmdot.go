@@ -35,6 +35,35 @@ func dot(s *fsm.State, sn *stateNames, visited map[*fsm.State]struct{}) []string
 	return res
 }
 
+// Mermaid generates a mermaid flowchart representation of an FSM, terminal states being drawn with a double circle
+func Mermaid(s *fsm.State) string {
+	trs := mermaid(s, mkStateNames(), map[*fsm.State]struct{}{})
+	return fmt.Sprintf("flowchart LR\n%s\n", strings.Join(trs, "\n"))
+}
+
+func mermaid(s *fsm.State, sn *stateNames, visited map[*fsm.State]struct{}) []string {
+	var res []string
+	if _, ok := visited[s]; ok {
+		return res
+	}
+	id := sn.id(s)
+	visited[s] = struct{}{}
+
+	shape := "\tS%d((S%d))"
+	if s.Terminal {
+		shape = "\tS%d(((S%d)))"
+	}
+	res = append(res, fmt.Sprintf(shape, id, id))
+
+	for _, tr := range s.Transitions {
+		label := strings.Replace(fmt.Sprint(tr.Matcher), `"`, "#quot;", -1)
+		res = append(res, fmt.Sprintf("\tS%d -->|\"%s\"| S%d", id, label, sn.id(tr.Next)))
+		res = append(res, mermaid(tr.Next, sn, visited)...)
+	}
+
+	return res
+}
+
 func mkStateNames() *stateNames {
 	return &stateNames{
 		counter: 1,
@@ -36,3 +36,26 @@ digraph G {
 }`)
 	require.Equal(t, expected, str)
 }
+
+func TestMermaid(t *testing.T) {
+	s1 := fsm.NewState()
+	s2 := fsm.NewState()
+	s3 := fsm.NewState()
+	s3.Terminal = true
+
+	s1.T(fsmtest.YepMatcher{}, s2)
+	s1.T(fsmtest.YepMatcher{}, s3)
+	s2.T(fsmtest.NopeMatcher{}, s3)
+
+	str := strings.TrimSpace(Mermaid(s1))
+
+	expected := strings.TrimSpace(`
+flowchart LR
+	S1((S1))
+	S1 -->|"<yep>"| S2
+	S2((S2))
+	S2 -->|"<nope>"| S3
+	S3(((S3)))
+	S1 -->|"<yep>"| S3`)
+	require.Equal(t, expected, str)
+}
@@ -0,0 +1,67 @@
+package fsm
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Trace is like Parse, except that it logs every transition tried into w and does not fill the containers.
+// The states are numbered like in the graphs generated by the fsmdot package
+func (s *State) Trace(args []string, w io.Writer) error {
+	m := &matching{args: args, failed: map[memoKey]bool{}, trace: &tracer{w: w, ids: stateIDs(s)}}
+	if _, ok := s.apply(args, false, m, nil); !ok {
+		return m.diagnose(s)
+	}
+	return nil
+}
+
+type tracer struct {
+	w   io.Writer
+	ids map[*State]int
+}
+
+func (t *tracer) log(prev *step, format string, args ...interface{}) {
+	depth := 0
+	if prev != nil {
+		depth = prev.depth + 1
+	}
+	_, _ = fmt.Fprintf(t.w, "%s%s\n", strings.Repeat("  ", depth), fmt.Sprintf(format, args...))
+}
+
+func (t *tracer) transition(prev *step, tr *Transition, matched bool) {
+	outcome := "no match"
+	if matched {
+		outcome = "matched"
+	}
+	t.log(prev, "  %v -> %s: %s", tr.Matcher, t.name(tr.Next), outcome)
+}
+
+func (t *tracer) name(s *State) string {
+	return fmt.Sprintf("S%d", t.ids[s])
+}
+
+// stateIDs numbers the states in the order they are first seen in a depth first walk
+func stateIDs(start *State) map[*State]int {
+	ids := map[*State]int{}
+	id := func(s *State) {
+		if _, ok := ids[s]; !ok {
+			ids[s] = len(ids) + 1
+		}
+	}
+	visited := map[*State]bool{}
+	var visit func(s *State)
+	visit = func(s *State) {
+		if visited[s] {
+			return
+		}
+		visited[s] = true
+		id(s)
+		for _, tr := range s.Transitions {
+			id(tr.Next)
+			visit(tr.Next)
+		}
+	}
+	visit(start)
+	return ids
+}
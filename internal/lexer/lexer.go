@@ -26,6 +26,8 @@ const (
 	TTOptions TokenType = "Options"
 	// TTRep ...
 	TTRep TokenType = "Rep"
+	// TTRepRange {2}, {1,3}, {2,}
+	TTRepRange TokenType = "RepRange"
 	// TTShortOpt -a, -f, ...
 	TTShortOpt TokenType = "ShortOpt"
 	// TTLongOpt --force, --retry, ...
@@ -36,6 +38,8 @@ const (
 	TTOptValue TokenType = "OptValue"
 	// TTDoubleDash --
 	TTDoubleDash TokenType = "DblDash"
+	// TTLiteral is a keyword which must appear verbatim, e.g. start, stop
+	TTLiteral TokenType = "Literal"
 )
 
 // Token has a type, a value and a position in the input
@@ -129,6 +133,26 @@ func Tokenize(usage string) ([]*Token, error) {
 			}
 			tkp(TTRep, "...", start)
 			pos++
+		case '{':
+			start := pos
+			pos++
+			for pos < eof && isDigit(usage[pos]) {
+				pos++
+			}
+			if pos == start+1 {
+				return nil, err("Was expecting a repetition count")
+			}
+			if pos < eof && usage[pos] == ',' {
+				pos++
+				for pos < eof && isDigit(usage[pos]) {
+					pos++
+				}
+			}
+			if pos >= eof || usage[pos] != '}' {
+				return nil, err("Unclosed repetition, was expecting '}'")
+			}
+			pos++
+			tkp(TTRepRange, usage[start:pos], start)
 		case '-':
 			start := pos
 			pos++
@@ -207,12 +231,26 @@ func Tokenize(usage string) ([]*Token, error) {
 						break
 					}
 				}
+				if pos < eof && isLowercase(usage[pos]) {
+					return nil, err("Invalid syntax, arguments must be in all caps")
+				}
 				s := usage[start:pos]
 				typ := TTArg
 				if s == "OPTIONS" {
 					typ = TTOptions
 				}
 				tkp(typ, s, start)
+			case isLowercase(c):
+				start := pos
+				for pos = pos + 1; pos < eof; pos++ {
+					if !isOkInLiteral(usage[pos]) {
+						break
+					}
+				}
+				if pos < eof && isUppercase(usage[pos]) {
+					return nil, err("Invalid syntax, literals must be in lower case")
+				}
+				tkp(TTLiteral, usage[start:pos], start)
 			default:
 				return nil, err("Unexpected input")
 			}
@@ -235,6 +273,10 @@ func isOkInArg(c uint8) bool {
 	return isUppercase(c) || isDigit(c) || c == '_'
 }
 
+func isOkInLiteral(c uint8) bool {
+	return isLowercase(c) || isDigit(c) || c == '_' || c == '-'
+}
+
 func isLetter(c uint8) bool {
 	return isLowercase(c) || isUppercase(c)
 }
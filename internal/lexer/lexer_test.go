@@ -38,6 +38,13 @@ func TestTokenize(t *testing.T) {
 		{"ARG ...", []*Token{{TTArg, "ARG", 0}, {TTRep, "...", 4}}},
 		{"[ARG...]", []*Token{{TTOpenSq, "[", 0}, {TTArg, "ARG", 1}, {TTRep, "...", 4}, {TTCloseSq, "]", 7}}},
 
+		{"{2}", []*Token{{TTRepRange, "{2}", 0}}},
+		{"ARG{2}", []*Token{{TTArg, "ARG", 0}, {TTRepRange, "{2}", 3}}},
+		{"ARG{1,3}", []*Token{{TTArg, "ARG", 0}, {TTRepRange, "{1,3}", 3}}},
+		{"ARG {10,}", []*Token{{TTArg, "ARG", 0}, {TTRepRange, "{10,}", 4}}},
+		{"-I{0,3}", []*Token{{TTShortOpt, "-I", 0}, {TTRepRange, "{0,3}", 2}}},
+		{"(ARG -a){2}", []*Token{{TTOpenPar, "(", 0}, {TTArg, "ARG", 1}, {TTShortOpt, "-a", 5}, {TTClosePar, ")", 7}, {TTRepRange, "{2}", 8}}},
+
 		{"|", []*Token{{TTChoice, "|", 0}}},
 		{"ARG|ARG2", []*Token{{TTArg, "ARG", 0}, {TTChoice, "|", 3}, {TTArg, "ARG2", 4}}},
 		{"ARG |ARG2", []*Token{{TTArg, "ARG", 0}, {TTChoice, "|", 4}, {TTArg, "ARG2", 5}}},
@@ -53,6 +60,11 @@ func TestTokenize(t *testing.T) {
 
 		{"-aBc", []*Token{{TTOptSeq, "aBc", 0}}},
 		{"--", []*Token{{TTDoubleDash, "--", 0}}},
+
+		{"start", []*Token{{TTLiteral, "start", 0}}},
+		{"dry-run", []*Token{{TTLiteral, "dry-run", 0}}},
+		{"v2_beta", []*Token{{TTLiteral, "v2_beta", 0}}},
+		{"service (start|stop) NAME", []*Token{{TTLiteral, "service", 0}, {TTOpenPar, "(", 8}, {TTLiteral, "start", 9}, {TTChoice, "|", 14}, {TTLiteral, "stop", 15}, {TTClosePar, ")", 19}, {TTArg, "NAME", 21}}},
 		{"=<bla>", []*Token{{TTOptValue, "=<bla>", 0}}},
 		{"=<bla-bla>", []*Token{{TTOptValue, "=<bla-bla>", 0}}},
 		{"=<bla--bla>", []*Token{{TTOptValue, "=<bla--bla>", 0}}},
@@ -104,11 +116,19 @@ func TestTokenizeErrors(t *testing.T) {
 		{"---x", 2},
 		{"-x-", 2},
 
+		{"{", 1},
+		{"{}", 1},
+		{"{,2}", 1},
+		{"{2", 2},
+		{"{2,", 3},
+		{"{2,3", 4},
+		{"{a}", 1},
+
 		{"=", 1},
 		{"=<", 2},
 		{"=<dsdf", 6},
 		{"=<>", 2},
-		{"a", 0},
+		{"aB", 1},
 		{"ARg", 2},
 		{"1ARG", 0},
 	}
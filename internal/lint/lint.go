@@ -0,0 +1,463 @@
+/*
+Package lint looks for mistakes in prepared FSMs which are not errors per se but make the spec parse in surprising ways.
+
+The analysis works on an abstraction of the FSM where every positional argument consumes the same kind of input
+(any word) and every option consumes its own name. Paths are tried in the transitions order, the first one which
+succeeds wins, so an alternative whose input is always accepted by the alternatives before it can never be used.
+*/
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/duanqy/cli/internal/fsm"
+	"github.com/duanqy/cli/internal/matcher"
+)
+
+// Check analyses the FSM built from a spec and returns the list of the problems found.
+// args is the list of the arguments declared by the command, to detect the ones the spec does not use
+func Check(s *fsm.State, args []*container.Container) []string {
+	l := &linter{
+		start:  node{kind: stateNode, s: s},
+		coacc:  map[node]bool{},
+		edges:  map[node][]edge{},
+		states: states(s),
+	}
+	l.coaccessible()
+
+	var res []string
+	res = append(res, l.unused(args)...)
+	res = append(res, l.loops()...)
+	res = append(res, l.shadowed()...)
+	res = append(res, l.ambiguities()...)
+	return res
+}
+
+type nodeKind int
+
+const (
+	// stateNode is an FSM state
+	stateNode nodeKind = iota
+	// loopNode is reached after an options group matched an option: it can consume more options of the same group
+	loopNode
+	// firstNode only has the edges of a single transition, it is used to compute the language of a transition
+	firstNode
+)
+
+type node struct {
+	kind nodeKind
+	s    *fsm.State
+	tr   *fsm.Transition
+}
+
+type symbol interface{}
+
+const (
+	// argSymbol is the input consumed by every positional argument
+	argSymbol = "ARG"
+	// optsEndSymbol is the input consumed by --
+	optsEndSymbol = "--"
+)
+
+type edge struct {
+	sym    symbol
+	assign *container.Container
+	to     node
+}
+
+type linter struct {
+	start  node
+	coacc  map[node]bool
+	edges  map[node][]edge
+	states []*fsm.State
+}
+
+func states(s *fsm.State) []*fsm.State {
+	var res []*fsm.State
+	visited := map[*fsm.State]bool{}
+	var visit func(s *fsm.State)
+	visit = func(s *fsm.State) {
+		if visited[s] {
+			return
+		}
+		visited[s] = true
+		res = append(res, s)
+		for _, tr := range s.Transitions {
+			visit(tr.Next)
+		}
+	}
+	visit(s)
+	return res
+}
+
+func (l *linter) terminal(n node) bool {
+	switch n.kind {
+	case stateNode:
+		return n.s.Terminal
+	case loopNode:
+		return n.tr.Next.Terminal
+	default:
+		return false
+	}
+}
+
+func (l *linter) edgesOf(n node) []edge {
+	if res, ok := l.edges[n]; ok {
+		return res
+	}
+	var res []edge
+	switch n.kind {
+	case stateNode:
+		for _, tr := range n.s.Transitions {
+			res = append(res, transitionEdges(tr)...)
+		}
+	case loopNode:
+		for _, o := range matcher.Containers(n.tr.Matcher) {
+			res = append(res, edge{sym: o, assign: o, to: n})
+		}
+		res = append(res, l.edgesOf(node{kind: stateNode, s: n.tr.Next})...)
+	case firstNode:
+		res = transitionEdges(n.tr)
+	}
+	l.edges[n] = res
+	return res
+}
+
+func transitionEdges(tr *fsm.Transition) []edge {
+	next := node{kind: stateNode, s: tr.Next}
+	cons := matcher.Containers(tr.Matcher)
+	switch {
+	case matcher.IsArg(tr.Matcher):
+		return []edge{{sym: argSymbol, assign: cons[0], to: next}}
+	case matcher.IsOptsEnd(tr.Matcher):
+		return []edge{{sym: optsEndSymbol, to: next}}
+	case len(cons) == 1:
+		return []edge{{sym: cons[0], assign: cons[0], to: next}}
+	case len(cons) > 1:
+		var res []edge
+		for _, o := range cons {
+			res = append(res, edge{sym: o, assign: o, to: node{kind: loopNode, tr: tr}})
+		}
+		return res
+	default:
+		return []edge{{sym: tr.Matcher, to: next}}
+	}
+}
+
+// coaccessible computes the set of nodes from which a terminal node can be reached
+func (l *linter) coaccessible() {
+	var all []node
+	visited := map[node]bool{}
+	var visit func(n node)
+	visit = func(n node) {
+		if visited[n] {
+			return
+		}
+		visited[n] = true
+		all = append(all, n)
+		for _, e := range l.edgesOf(n) {
+			visit(e.to)
+		}
+	}
+	visit(l.start)
+	for _, s := range l.states {
+		for _, tr := range s.Transitions {
+			visit(node{kind: firstNode, tr: tr})
+		}
+	}
+
+	for _, n := range all {
+		if l.terminal(n) {
+			l.coacc[n] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, n := range all {
+			if l.coacc[n] {
+				continue
+			}
+			for _, e := range l.edgesOf(n) {
+				if l.coacc[e.to] {
+					l.coacc[n] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// unused reports the declared arguments which do not appear in the spec
+func (l *linter) unused(args []*container.Container) []string {
+	used := map[*container.Container]bool{}
+	for _, s := range l.states {
+		for _, tr := range s.Transitions {
+			if matcher.IsArg(tr.Matcher) {
+				used[matcher.Containers(tr.Matcher)[0]] = true
+			}
+		}
+	}
+
+	var res []string
+	for _, a := range args {
+		if !used[a] {
+			res = append(res, fmt.Sprintf("argument %s is declared but not used in the spec", a.Name))
+		}
+	}
+	return res
+}
+
+// loops reports the cycles made of transitions which can match without consuming any input:
+// options set from the environment always match
+func (l *linter) loops() []string {
+	nullable := func(tr *fsm.Transition) bool {
+		if matcher.IsArg(tr.Matcher) {
+			return false
+		}
+		for _, o := range matcher.Containers(tr.Matcher) {
+			if o.EnvVar != "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	type step struct {
+		from *fsm.State
+		tr   *fsm.Transition
+	}
+	var (
+		res      []string
+		seen     = map[string]bool{}
+		visiting = map[*fsm.State]bool{}
+		done     = map[*fsm.State]bool{}
+		path     []step
+		visit    func(s *fsm.State)
+	)
+	visit = func(s *fsm.State) {
+		visiting[s] = true
+		for _, tr := range s.Transitions {
+			if !nullable(tr) {
+				continue
+			}
+			path = append(path, step{s, tr})
+			switch {
+			case visiting[tr.Next]:
+				var names []string
+				for i := len(path) - 1; i >= 0; i-- {
+					names = append([]string{fmt.Sprintf("%v", path[i].tr.Matcher)}, names...)
+					if path[i].from == tr.Next {
+						break
+					}
+				}
+				msg := fmt.Sprintf("spec can loop forever: %s can match without consuming input when set from the environment", strings.Join(names, " "))
+				if !seen[msg] {
+					seen[msg] = true
+					res = append(res, msg)
+				}
+			case !done[tr.Next]:
+				visit(tr.Next)
+			}
+			path = path[:len(path)-1]
+		}
+		visiting[s] = false
+		done[s] = true
+	}
+	for _, s := range l.states {
+		if !done[s] {
+			visit(s)
+		}
+	}
+	return res
+}
+
+// shadowed reports the transitions which are never used because the transitions tried before them always match
+func (l *linter) shadowed() []string {
+	// transitions are shared between states by the FSM simplification, so they are identified by their origin too
+	type key struct {
+		s  *fsm.State
+		tr *fsm.Transition
+	}
+	shadowed := map[key]bool{}
+	for _, s := range l.states {
+		for i, tr := range s.Transitions {
+			if i == 0 || !l.coacc[node{kind: firstNode, tr: tr}] {
+				continue
+			}
+			var before []node
+			for _, prev := range s.Transitions[:i] {
+				before = append(before, node{kind: firstNode, tr: prev})
+			}
+			shadowed[key{s, tr}] = l.included(node{kind: firstNode, tr: tr}, before)
+		}
+	}
+
+	var (
+		res       []string
+		seen      = map[string]bool{}
+		args      []*container.Container
+		reachable = map[*container.Container]bool{}
+		visited   = map[*fsm.State]bool{}
+		visit     func(s *fsm.State)
+	)
+	visit = func(s *fsm.State) {
+		if visited[s] {
+			return
+		}
+		visited[s] = true
+		for _, tr := range s.Transitions {
+			if shadowed[key{s, tr}] {
+				if !matcher.IsArg(tr.Matcher) {
+					msg := fmt.Sprintf("%v is shadowed by another alternative and is never matched", tr.Matcher)
+					if !seen[msg] {
+						seen[msg] = true
+						res = append(res, msg)
+					}
+				}
+				continue
+			}
+			if matcher.IsArg(tr.Matcher) {
+				reachable[matcher.Containers(tr.Matcher)[0]] = true
+			}
+			visit(tr.Next)
+		}
+	}
+	visit(l.start.s)
+
+	for _, s := range l.states {
+		for _, tr := range s.Transitions {
+			if !matcher.IsArg(tr.Matcher) {
+				continue
+			}
+			con := matcher.Containers(tr.Matcher)[0]
+			if !reachable[con] && !seen[con.Name] {
+				seen[con.Name] = true
+				args = append(args, con)
+			}
+		}
+	}
+	for _, con := range args {
+		res = append(res, fmt.Sprintf("argument %s can never be reached: the alternatives before it always match", con.Name))
+	}
+	return res
+}
+
+// included checks whether every input accepted from the node a is also accepted from one of the nodes bs
+func (l *linter) included(a node, bs []node) bool {
+	type item struct {
+		a  node
+		bs []node
+	}
+	ids := map[node]int{}
+	key := func(it item) string {
+		var parts []int
+		for _, b := range it.bs {
+			if _, ok := ids[b]; !ok {
+				ids[b] = len(ids) + 1
+			}
+			parts = append(parts, ids[b])
+		}
+		sort.Ints(parts)
+		if _, ok := ids[it.a]; !ok {
+			ids[it.a] = len(ids) + 1
+		}
+		return fmt.Sprint(ids[it.a], parts)
+	}
+
+	visited := map[string]bool{}
+	queue := []item{{a, bs}}
+	visited[key(queue[0])] = true
+	for len(queue) > 0 {
+		it := queue[0]
+		queue = queue[1:]
+
+		if l.terminal(it.a) {
+			accepted := false
+			for _, b := range it.bs {
+				accepted = accepted || l.terminal(b)
+			}
+			if !accepted {
+				return false
+			}
+		}
+
+		for _, e := range l.edgesOf(it.a) {
+			if !l.coacc[e.to] {
+				continue
+			}
+			var next []node
+			dedup := map[node]bool{}
+			for _, b := range it.bs {
+				for _, be := range l.edgesOf(b) {
+					if be.sym == e.sym && !dedup[be.to] {
+						dedup[be.to] = true
+						next = append(next, be.to)
+					}
+				}
+			}
+			if len(next) == 0 {
+				return false
+			}
+			nit := item{e.to, next}
+			if k := key(nit); !visited[k] {
+				visited[k] = true
+				queue = append(queue, nit)
+			}
+		}
+	}
+	return true
+}
+
+// ambiguities reports the arguments between which some input can be split in more than one way
+func (l *linter) ambiguities() []string {
+	type pair struct {
+		a, b *container.Container
+	}
+	type item struct {
+		n1, n2 node
+		div    pair
+	}
+
+	var (
+		res     []string
+		seen    = map[pair]bool{}
+		visited = map[item]bool{}
+		queue   = []item{{n1: l.start, n2: l.start}}
+	)
+	visited[queue[0]] = true
+	for len(queue) > 0 {
+		it := queue[0]
+		queue = queue[1:]
+
+		if it.div.a != nil && l.terminal(it.n1) && l.terminal(it.n2) && !seen[it.div] {
+			seen[it.div] = true
+			seen[pair{it.div.b, it.div.a}] = true
+			res = append(res, fmt.Sprintf("ambiguous spec: the same input can be assigned to either %s or %s", it.div.a.Name, it.div.b.Name))
+		}
+
+		for _, e1 := range l.edgesOf(it.n1) {
+			if !l.coacc[e1.to] {
+				continue
+			}
+			for _, e2 := range l.edgesOf(it.n2) {
+				if e1.sym != e2.sym || !l.coacc[e2.to] {
+					continue
+				}
+				div := it.div
+				if div.a == nil && e1.assign != e2.assign {
+					div = pair{e1.assign, e2.assign}
+				}
+				nit := item{e1.to, e2.to, div}
+				if !visited[nit] {
+					visited[nit] = true
+					queue = append(queue, nit)
+				}
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,147 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/duanqy/cli/internal/fsm"
+	"github.com/duanqy/cli/internal/lexer"
+	"github.com/duanqy/cli/internal/parser"
+	"github.com/duanqy/cli/internal/values"
+	"github.com/stretchr/testify/require"
+)
+
+func mkFsm(t *testing.T, spec string, envOpts ...string) (*fsm.State, []*container.Container) {
+	params := parser.Params{
+		Spec:       spec,
+		OptionsIdx: map[string]*container.Container{},
+		ArgsIdx:    map[string]*container.Container{},
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		con := &container.Container{Name: name, Names: []string{"-" + name}, Value: values.NewBool(new(bool), false)}
+		for _, env := range envOpts {
+			if env == name {
+				con.EnvVar = strings.ToUpper(name)
+			}
+		}
+		params.Options = append(params.Options, con)
+		params.OptionsIdx["-"+name] = con
+	}
+	for _, name := range []string{"SRC", "DST", "ARG", "ARG2", "UNUSED"} {
+		con := &container.Container{Name: name, Value: values.NewString(new(string), "")}
+		params.Args = append(params.Args, con)
+		params.ArgsIdx[name] = con
+	}
+
+	tokens, err := lexer.Tokenize(spec)
+	require.NoError(t, err)
+	s, err := parser.Parse(tokens, params)
+	require.NoError(t, err)
+
+	var used []*container.Container
+	for _, a := range params.Args {
+		if strings.Contains(spec, a.Name) {
+			used = append(used, a)
+		}
+	}
+	return s, used
+}
+
+func TestCheckSoundSpecs(t *testing.T) {
+	specs := []string{
+		"",
+		"SRC",
+		"SRC... DST",
+		"[-a] SRC... DST",
+		"[OPTIONS] SRC [DST]",
+		"[-a | -b] SRC",
+		"-a | (-b SRC)",
+		"[-abc] SRC... [-- ARG...]",
+		"SRC (-a | -b)...",
+		"(SRC DST)...",
+		"(start | stop) SRC",
+		"[-a] [force] SRC",
+	}
+
+	for _, spec := range specs {
+		t.Run(spec, func(t *testing.T) {
+			s, args := mkFsm(t, spec)
+			require.Empty(t, Check(s, args))
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		spec     string
+		env      []string
+		expected []string
+	}{
+		{
+			spec: "[ARG...] [ARG2...]",
+			expected: []string{
+				"argument ARG2 can never be reached: the alternatives before it always match",
+				"ambiguous spec: the same input can be assigned to either ARG or ARG2",
+			},
+		},
+		{
+			spec: "[SRC] [DST]",
+			expected: []string{
+				"ambiguous spec: the same input can be assigned to either SRC or DST",
+			},
+		},
+		{
+			spec: "SRC [ARG] [DST]",
+			expected: []string{
+				"ambiguous spec: the same input can be assigned to either ARG or DST",
+			},
+		},
+		{
+			spec: "(SRC | DST)",
+			expected: []string{
+				"argument DST can never be reached: the alternatives before it always match",
+				"ambiguous spec: the same input can be assigned to either SRC or DST",
+			},
+		},
+		{
+			spec: "[-a | [-a]]",
+			expected: []string{
+				"-a is shadowed by another alternative and is never matched",
+			},
+		},
+		{
+			spec: "(-a)...",
+			env:  []string{"a"},
+			expected: []string{
+				"spec can loop forever: -a can match without consuming input when set from the environment",
+			},
+		},
+		{
+			spec: "(-a -b)...",
+			env:  []string{"a", "b"},
+			expected: []string{
+				"spec can loop forever: -b -a can match without consuming input when set from the environment",
+			},
+		},
+	}
+
+	for _, cas := range cases {
+		t.Run(cas.spec, func(t *testing.T) {
+			s, args := mkFsm(t, cas.spec, cas.env...)
+			require.Equal(t, cas.expected, Check(s, args))
+		})
+	}
+}
+
+func TestCheckUnusedArgs(t *testing.T) {
+	s, args := mkFsm(t, "SRC")
+	unused := &container.Container{Name: "UNUSED"}
+
+	require.Equal(t, []string{"argument UNUSED is declared but not used in the spec"}, Check(s, append(args, unused)))
+}
+
+func TestCheckNoLoopWithoutEnv(t *testing.T) {
+	s, args := mkFsm(t, "(-a)...", "b")
+	require.Empty(t, Check(s, args))
+}
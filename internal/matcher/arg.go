@@ -11,15 +11,21 @@ func NewArg(a *container.Container) Matcher {
 	return &arg{arg: a}
 }
 
+// NewNumberArg creates an (positional) argument matcher which also accepts negative numbers, e.g. -5
+func NewNumberArg(a *container.Container) Matcher {
+	return &arg{arg: a, numbers: true}
+}
+
 type arg struct {
-	arg *container.Container
+	arg     *container.Container
+	numbers bool
 }
 
 func (arg *arg) Match(args []string, c *ParseContext) (bool, []string) {
 	if len(args) == 0 {
 		return false, args
 	}
-	if !c.RejectOptions && strings.HasPrefix(args[0], "-") && args[0] != "-" {
+	if !c.RejectOptions && strings.HasPrefix(args[0], "-") && args[0] != "-" && !(arg.numbers && isNegativeNumber(args[0])) {
 		return false, args
 	}
 	c.Args[arg.arg] = append(c.Args[arg.arg], args[0])
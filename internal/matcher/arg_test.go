@@ -41,3 +41,21 @@ func TestArgMatcher(t *testing.T) {
 		require.True(t, ok, "arg should match options when the reject flag is set")
 	}
 }
+
+func TestNumberArgMatcher(t *testing.T) {
+	a := &container.Container{Name: "X"}
+
+	pc := NewParseContext()
+	ok, nargs := NewNumberArg(a).Match([]string{"-5", "b"}, &pc)
+	require.True(t, ok, "number arg should match negative numbers")
+	require.Equal(t, []string{"b"}, nargs)
+	require.Equal(t, []string{"-5"}, pc.Args[a])
+
+	pc = NewParseContext()
+	ok, _ = NewNumberArg(a).Match([]string{"-v"}, &pc)
+	require.False(t, ok, "number arg should not match options")
+
+	pc = NewParseContext()
+	ok, _ = NewArg(a).Match([]string{"-5"}, &pc)
+	require.False(t, ok, "arg should not match negative numbers")
+}
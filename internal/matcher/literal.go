@@ -0,0 +1,29 @@
+package matcher
+
+import "github.com/duanqy/cli/internal/container"
+
+// NewLiteral creates a matcher for a keyword which must appear verbatim in the args, e.g. start.
+// The keyword is the name of the provided container, which is set to true when matched
+func NewLiteral(l *container.Container) Matcher {
+	return &literal{lit: l}
+}
+
+type literal struct {
+	lit *container.Container
+}
+
+func (l *literal) Match(args []string, c *ParseContext) (bool, []string) {
+	if len(args) == 0 || args[0] != l.lit.Name {
+		return false, args
+	}
+	c.Args[l.lit] = append(c.Args[l.lit], "true")
+	return true, args[1:]
+}
+
+func (*literal) Priority() int {
+	return 5
+}
+
+func (l *literal) String() string {
+	return l.lit.Name
+}
@@ -0,0 +1,42 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiteralMatcher(t *testing.T) {
+	l := &container.Container{Name: "start"}
+	litMatcher := literal{lit: l}
+
+	require.Equal(t, "start", litMatcher.String())
+
+	{
+		pc := NewParseContext()
+		ok, nargs := litMatcher.Match(nil, &pc)
+		require.False(t, ok, "literal should not match")
+		require.Nil(t, nargs, "literal should not consume anything")
+		require.Nil(t, pc.Args[l], "literal should not store anything")
+	}
+	{
+		pc := NewParseContext()
+		ok, nargs := litMatcher.Match([]string{"stop", "x"}, &pc)
+		require.False(t, ok, "literal should not match another word")
+		require.Equal(t, []string{"stop", "x"}, nargs, "literal should not consume anything")
+	}
+	{
+		pc := NewParseContext()
+		ok, nargs := litMatcher.Match([]string{"start", "x"}, &pc)
+		require.True(t, ok, "literal should match")
+		require.Equal(t, []string{"x"}, nargs, "literal should consume the matched word")
+		require.Equal(t, []string{"true"}, pc.Args[l], "literal should be recorded as matched")
+	}
+	{
+		pc := NewParseContext()
+		pc.RejectOptions = true
+		ok, _ := litMatcher.Match([]string{"start"}, &pc)
+		require.True(t, ok, "literal should match after --")
+	}
+}
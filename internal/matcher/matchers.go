@@ -1,5 +1,7 @@
 package matcher
 
+import "github.com/duanqy/cli/internal/container"
+
 /*
 Matcher is used to parse and consume the args and populate the ParseContext
 */
@@ -18,3 +20,43 @@ func IsShortcut(matcher Matcher) bool {
 	_, ok := matcher.(shortcut)
 	return ok
 }
+
+// IsArg is a helper to determine whether a given matcher is a positional argument matcher
+func IsArg(matcher Matcher) bool {
+	_, ok := matcher.(*arg)
+	return ok
+}
+
+// IsLiteral is a helper to determine whether a given matcher is a keyword matcher
+func IsLiteral(matcher Matcher) bool {
+	_, ok := matcher.(*literal)
+	return ok
+}
+
+// IsOptions is a helper to determine whether a given matcher is a group of options, e.g. [OPTIONS]
+func IsOptions(matcher Matcher) bool {
+	_, ok := matcher.(*options)
+	return ok
+}
+
+// IsOptsEnd is a helper to determine whether a given matcher is the -- operator matcher
+func IsOptsEnd(matcher Matcher) bool {
+	_, ok := matcher.(optsEnd)
+	return ok
+}
+
+// Containers returns the option, argument or literal containers a given matcher can fill, or nil for the special matchers
+func Containers(matcher Matcher) []*container.Container {
+	switch m := matcher.(type) {
+	case *arg:
+		return []*container.Container{m.arg}
+	case *literal:
+		return []*container.Container{m.lit}
+	case *opt:
+		return []*container.Container{m.theOne}
+	case *options:
+		return m.options
+	default:
+		return nil
+	}
+}
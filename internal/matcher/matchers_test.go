@@ -5,6 +5,7 @@ import (
 
 	"fmt"
 
+	"github.com/duanqy/cli/internal/container"
 	"github.com/stretchr/testify/require"
 )
 
@@ -31,6 +32,7 @@ func TestPriority(t *testing.T) {
 		opt      = NewOpt(nil, nil)
 		arg      = NewArg(nil)
 		options  = NewOptions(nil, nil)
+		literal  = NewLiteral(nil)
 	)
 
 	cases := []struct {
@@ -51,8 +53,11 @@ func TestPriority(t *testing.T) {
 		{opt, options},
 		{opt, arg},
 
-		// options comes before arg
-		{options, arg},
+		// options comes before literal
+		{options, literal},
+
+		// literal comes before arg
+		{literal, arg},
 	}
 
 	for _, cas := range cases {
@@ -72,6 +77,7 @@ func TestMatchersComparable(t *testing.T) {
 		NewOpt(nil, nil),
 		NewOptions(nil, nil),
 		NewArg(nil),
+		NewLiteral(nil),
 		NewOptsEnd(),
 	}
 
@@ -81,3 +87,27 @@ func TestMatchersComparable(t *testing.T) {
 		}
 	}
 }
+
+func TestContainers(t *testing.T) {
+	var (
+		a   = &container.Container{Name: "A"}
+		o1  = &container.Container{Name: "-a"}
+		o2  = &container.Container{Name: "-b"}
+		l   = &container.Container{Name: "start"}
+		arg = NewArg(a)
+	)
+
+	require.True(t, IsArg(arg))
+	require.False(t, IsArg(NewOpt(o1, nil)))
+	require.True(t, IsOptsEnd(NewOptsEnd()))
+	require.False(t, IsOptsEnd(NewShortcut()))
+
+	require.Equal(t, []*container.Container{a}, Containers(arg))
+	require.Equal(t, []*container.Container{o1}, Containers(NewOpt(o1, nil)))
+	require.Equal(t, []*container.Container{o1, o2}, Containers(NewOptions([]*container.Container{o1, o2}, nil)))
+	require.Equal(t, []*container.Container{l}, Containers(NewLiteral(l)))
+	require.True(t, IsLiteral(NewLiteral(l)))
+	require.False(t, IsLiteral(arg))
+	require.Nil(t, Containers(NewShortcut()))
+	require.Nil(t, Containers(NewOptsEnd()))
+}
@@ -0,0 +1,59 @@
+package matcher
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/duanqy/cli/internal/values"
+)
+
+// HasDigitOptions checks whether index contains a short option named after a digit, e.g. -1, or an option with a
+// numeric shorthand, e.g. -5 for -n 5, in which case the negative numbers are read as options
+func HasDigitOptions(index map[string]*container.Container) bool {
+	for name, con := range index {
+		if con.NumericShorthand || len(name) == 2 && name[1] >= '0' && name[1] <= '9' {
+			return true
+		}
+	}
+	return false
+}
+
+// isNegativeNumber checks whether arg looks like a negative number, e.g. -5, -.5 or -1e3, rather than an option
+func isNegativeNumber(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' || !isDigit(arg[1]) && !(arg[1] == '.' && len(arg) > 2 && isDigit(arg[2])) {
+		return false
+	}
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
+
+// acceptsValue checks whether value, found after the option con, can be its value
+func acceptsValue(con *container.Container, value string, index map[string]*container.Container) bool {
+	if !strings.HasPrefix(value, "-") {
+		return true
+	}
+	return values.IsNumeric(con.Value) && isNegativeNumber(value) && !HasDigitOptions(index)
+}
+
+// shorthandOption returns the option with a numeric shorthand set by arg, e.g. -5, if any
+func shorthandOption(arg string, index map[string]*container.Container) *container.Container {
+	if len(arg) < 2 || index[arg[:2]] != nil {
+		return nil
+	}
+	for _, c := range arg[1:] {
+		if c < '0' || c > '9' {
+			return nil
+		}
+	}
+	for _, con := range index {
+		if con.NumericShorthand {
+			return con
+		}
+	}
+	return nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
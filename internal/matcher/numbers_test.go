@@ -0,0 +1,28 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/duanqy/cli/internal/values"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNegativeNumber(t *testing.T) {
+	for _, arg := range []string{"-5", "-10", "-3.5", "-.5", "-1e3"} {
+		require.True(t, isNegativeNumber(arg), arg)
+	}
+	for _, arg := range []string{"-", "5", "-x", "-5x", "-.", "-inf", "-nan", "--5"} {
+		require.False(t, isNegativeNumber(arg), arg)
+	}
+}
+
+func TestHasDigitOptions(t *testing.T) {
+	flag := &container.Container{Names: []string{"-f"}, Value: values.NewBool(new(bool), false)}
+	one := &container.Container{Names: []string{"-1"}, Value: values.NewBool(new(bool), false)}
+	lines := &container.Container{Names: []string{"-n"}, Value: values.NewInt(new(int), 0), NumericShorthand: true}
+
+	require.False(t, HasDigitOptions(map[string]*container.Container{"-f": flag}))
+	require.True(t, HasDigitOptions(map[string]*container.Container{"-f": flag, "-1": one}))
+	require.True(t, HasDigitOptions(map[string]*container.Container{"-n": lines}))
+}
@@ -103,7 +103,7 @@ func (o *opt) matchLongOpt(args []string, idx int, c *ParseContext) (bool, int,
 			return false, 2, args
 		}
 		value := args[idx+1]
-		if strings.HasPrefix(value, "-") {
+		if !acceptsValue(opt, value, o.index) {
 			return false, 0, args
 		}
 		c.Opts[o.theOne] = append(c.Opts[o.theOne], value)
@@ -117,6 +117,14 @@ func (o *opt) matchShortOpt(args []string, idx int, c *ParseContext) (bool, int,
 		return false, 0, args
 	}
 
+	if opt := shorthandOption(arg, o.index); opt != nil {
+		if opt != o.theOne {
+			return false, 1, args
+		}
+		c.Opts[o.theOne] = append(c.Opts[o.theOne], arg[1:])
+		return true, 1, removeStringAt(idx, args)
+	}
+
 	if strings.HasPrefix(arg[2:], "=") {
 		name := arg[0:2]
 		opt := o.index[name]
@@ -168,7 +176,7 @@ func (o *opt) matchShortOpt(args []string, idx int, c *ParseContext) (bool, int,
 			}
 
 			value = args[idx+1]
-			if strings.HasPrefix(value, "-") {
+			if !acceptsValue(opt, value, o.index) {
 				return false, 0, args
 			}
 			c.Opts[o.theOne] = append(c.Opts[o.theOne], value)
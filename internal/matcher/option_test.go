@@ -165,3 +165,55 @@ func TestOptNegatives(t *testing.T) {
 		}
 	}
 }
+
+func TestOptNegativeNumberValues(t *testing.T) {
+	offset := &container.Container{Names: []string{"-o", "--offset"}, Value: values.NewInt(new(int), 0)}
+	name := &container.Container{Names: []string{"-n", "--name"}, Value: values.NewString(new(string), "")}
+	index := map[string]*container.Container{"-o": offset, "--offset": offset, "-n": name, "--name": name}
+
+	cases := []struct {
+		con   *container.Container
+		args  []string
+		ok    bool
+		nargs []string
+	}{
+		{offset, []string{"--offset", "-10", "x"}, true, []string{"x"}},
+		{offset, []string{"-o", "-10"}, true, []string{}},
+		{offset, []string{"-o", "-.5"}, true, []string{}},
+		{offset, []string{"-o", "-x"}, false, []string{"-o", "-x"}},
+		{name, []string{"--name", "-10"}, false, []string{"--name", "-10"}},
+	}
+	for _, cas := range cases {
+		t.Run(fmt.Sprintf("%#v", cas.args), func(t *testing.T) {
+			pc := NewParseContext()
+			ok, nargs := (&opt{theOne: cas.con, index: index}).Match(cas.args, &pc)
+			require.Equal(t, cas.ok, ok)
+			require.Equal(t, cas.nargs, nargs)
+		})
+	}
+
+	t.Run("digit options", func(t *testing.T) {
+		one := &container.Container{Names: []string{"-1"}, Value: values.NewBool(new(bool), false)}
+		withDigits := map[string]*container.Container{"-o": offset, "--offset": offset, "-1": one}
+
+		pc := NewParseContext()
+		ok, _ := (&opt{theOne: offset, index: withDigits}).Match([]string{"-o", "-1"}, &pc)
+		require.False(t, ok, "-1 should be read as an option when a digit option is declared")
+	})
+}
+
+func TestOptNumericShorthand(t *testing.T) {
+	lines := &container.Container{Names: []string{"-n", "--lines"}, Value: values.NewInt(new(int), 0), NumericShorthand: true}
+	quiet := &container.Container{Names: []string{"-q"}, Value: values.NewBool(new(bool), false)}
+	index := map[string]*container.Container{"-n": lines, "--lines": lines, "-q": quiet}
+
+	pc := NewParseContext()
+	ok, nargs := (&opt{theOne: lines, index: index}).Match([]string{"-q", "-15", "x"}, &pc)
+	require.True(t, ok)
+	require.Equal(t, []string{"-q", "x"}, nargs)
+	require.Equal(t, []string{"15"}, pc.Opts[lines])
+
+	pc = NewParseContext()
+	ok, _ = (&opt{theOne: quiet, index: index}).Match([]string{"-15"}, &pc)
+	require.False(t, ok)
+}
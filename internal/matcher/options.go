@@ -47,7 +47,9 @@ func (om *options) try(args []string, c *ParseContext) (bool, []string) {
 			continue
 		}
 		if ok, nargs := (&opt{theOne: o, index: om.index}).Match(args, c); ok {
-			if o.ValueSetFromEnv {
+			// an option set from its env var matches without consuming anything: exclude it to stop the loop, but
+			// keep it available while it consumes args, e.g. for the repeated values of a multi-valued option
+			if o.ValueSetFromEnv && sameStrings(args, nargs) {
 				c.ExcludedOpts[o] = struct{}{}
 			}
 			return true, nargs
@@ -104,3 +104,20 @@ func TestOptsMatcherInfiniteLoop(t *testing.T) {
 	}
 
 }
+
+func TestOptsMatcherRepeatedEnvOption(t *testing.T) {
+	opts := options{
+		options: []*container.Container{
+			{Names: []string{"--tags"}, Value: values.NewStrings(new([]string), nil), ValueSetFromEnv: true},
+		},
+		index: map[string]*container.Container{},
+	}
+	opts.index["--tags"] = opts.options[0]
+
+	pc := NewParseContext()
+	ok, nargs := opts.Match([]string{"--tags", "a", "--tags", "b", "x"}, &pc)
+
+	require.True(t, ok)
+	require.Equal(t, []string{"x"}, nargs)
+	require.Equal(t, []string{"a", "b"}, pc.Opts[opts.options[0]])
+}
@@ -2,11 +2,14 @@ package parser
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/duanqy/cli/internal/container"
 	"github.com/duanqy/cli/internal/fsm"
 	"github.com/duanqy/cli/internal/lexer"
 	"github.com/duanqy/cli/internal/matcher"
+	"github.com/duanqy/cli/internal/values"
 )
 
 // Params are used to cofigure the parser
@@ -16,27 +19,89 @@ type Params struct {
 	OptionsIdx map[string]*container.Container
 	Args       []*container.Container
 	ArgsIdx    map[string]*container.Container
+	// LiteralsIdx receives the containers of the keywords used in the spec, created as needed
+	LiteralsIdx map[string]*container.Container
+	// SingleDashLongOptions allows long options with a single dash, e.g. -timeout for --timeout
+	SingleDashLongOptions bool
+}
+
+// Bound is the allowed number of occurrences of an argument or an option repeated with {min,max} in a spec
+type Bound struct {
+	Container *container.Container
+	// Min is the minimum number of occurrences
+	Min int
+	// Max is the maximum number of occurrences, or -1 if unbounded
+	Max int
 }
 
 // Parse transforms a slice of tokens into an FSM or returns an ParseError
 func Parse(tokens []*lexer.Token, params Params) (*fsm.State, error) {
-	p := &parser{
-		spec:       params.Spec,
-		options:    params.Options,
-		optionsIdx: params.OptionsIdx,
-		args:       params.Args,
-		argsIdx:    params.ArgsIdx,
-		tokens:     tokens,
-	}
+	p := newParser(tokens, params)
 	return p.parse()
 }
 
+// ParseRelaxed is like Parse, except that the bounded repetitions, e.g. ARG{2,3}, accept any number of occurrences.
+// The bounds of the repeated arguments and options are returned instead, to be checked after the fact
+func ParseRelaxed(tokens []*lexer.Token, params Params) (*fsm.State, []Bound, error) {
+	p := newParser(tokens, params)
+	p.relaxed = true
+	s, err := p.parse()
+	return s, p.bounds, err
+}
+
+/*
+Explain looks for a precise reason why args were rejected by the FSM built from tokens and params.
+It returns nil if none could be found.
+*/
+func Explain(tokens []*lexer.Token, params Params, args []string) error {
+	s, bounds, err := ParseRelaxed(tokens, params)
+	if err != nil || len(bounds) == 0 {
+		return nil
+	}
+	pc, ok := s.Match(args)
+	if !ok {
+		return nil
+	}
+	for _, b := range bounds {
+		name := b.Container.Name
+		count := len(pc.Args[b.Container])
+		if len(b.Container.Names) > 0 {
+			name = b.Container.Names[0]
+			count = len(pc.Opts[b.Container])
+		}
+		switch {
+		case b.Min == b.Max && count != b.Min:
+			return fmt.Errorf("expected %d %s, got %d", b.Min, name, count)
+		case count < b.Min:
+			return fmt.Errorf("expected at least %d %s, got %d", b.Min, name, count)
+		case b.Max >= 0 && count > b.Max:
+			return fmt.Errorf("expected at most %d %s, got %d", b.Max, name, count)
+		}
+	}
+	return nil
+}
+
+func newParser(tokens []*lexer.Token, params Params) *parser {
+	return &parser{
+		spec:        params.Spec,
+		options:     params.Options,
+		optionsIdx:  params.OptionsIdx,
+		args:        params.Args,
+		argsIdx:     params.ArgsIdx,
+		literalsIdx: params.LiteralsIdx,
+		singleDash:  params.SingleDashLongOptions,
+		tokens:      tokens,
+	}
+}
+
 type parser struct {
-	spec       string
-	options    []*container.Container
-	optionsIdx map[string]*container.Container
-	args       []*container.Container
-	argsIdx    map[string]*container.Container
+	spec        string
+	options     []*container.Container
+	optionsIdx  map[string]*container.Container
+	args        []*container.Container
+	argsIdx     map[string]*container.Container
+	literalsIdx map[string]*container.Container
+	singleDash  bool
 
 	tokens []*lexer.Token
 
@@ -45,6 +110,9 @@ type parser struct {
 	matchedToken *lexer.Token
 
 	rejectOptions bool
+
+	relaxed bool
+	bounds  []Bound
 }
 
 func (p *parser) parse() (s *fsm.State, err error) {
@@ -117,7 +185,10 @@ func (p *parser) choice() (*fsm.State, *fsm.State) {
 
 func (p *parser) atom() (*fsm.State, *fsm.State) {
 	start := fsm.NewState()
-	var end *fsm.State
+	var (
+		end    *fsm.State
+		single *container.Container
+	)
 	switch {
 	case p.eof():
 		panic("Unexpected end of input")
@@ -128,7 +199,12 @@ func (p *parser) atom() (*fsm.State, *fsm.State) {
 			p.back()
 			panic(fmt.Sprintf("Undeclared arg %s", name))
 		}
-		end = start.T(matcher.NewArg(arg), fsm.NewState())
+		end = start.T(p.arg(arg), fsm.NewState())
+		single = arg
+	case p.found(lexer.TTLiteral):
+		lit := p.literal(p.matchedToken.Val)
+		end = start.T(matcher.NewLiteral(lit), fsm.NewState())
+		single = lit
 	case p.found(lexer.TTOptions):
 		if p.rejectOptions {
 			p.back()
@@ -148,7 +224,8 @@ func (p *parser) atom() (*fsm.State, *fsm.State) {
 			panic(fmt.Sprintf("Undeclared option %s", name))
 		}
 		end = start.T(matcher.NewOpt(opt, p.optionsIdx), fsm.NewState())
-		p.found(lexer.TTOptValue)
+		p.placeholder(opt)
+		single = opt
 	case p.found(lexer.TTLongOpt):
 		if p.rejectOptions {
 			p.back()
@@ -161,14 +238,21 @@ func (p *parser) atom() (*fsm.State, *fsm.State) {
 			panic(fmt.Sprintf("Undeclared option %s", name))
 		}
 		end = start.T(matcher.NewOpt(opt, p.optionsIdx), fsm.NewState())
-		p.found(lexer.TTOptValue)
+		p.placeholder(opt)
+		single = opt
 	case p.found(lexer.TTOptSeq):
 		if p.rejectOptions {
 			p.back()
 			panic("No options after --")
 		}
-		end = fsm.NewState()
 		sq := p.matchedToken.Val
+		if opt, declared := p.optionsIdx["--"+sq]; declared && p.singleDash {
+			end = start.T(matcher.NewOpt(opt, p.optionsIdx), fsm.NewState())
+			p.placeholder(opt)
+			single = opt
+			break
+		}
+		end = fsm.NewState()
 		var opts []*container.Container
 		for i := range sq {
 			sn := sq[i : i+1]
@@ -192,18 +276,148 @@ func (p *parser) atom() (*fsm.State, *fsm.State) {
 		end = start.T(matcher.NewOptsEnd(), fsm.NewState())
 		return start, end
 	default:
-		panic("Unexpected input: was expecting a command or a positional argument or an option")
+		panic("Unexpected input: was expecting a command, a keyword, a positional argument or an option")
 	}
-	if p.found(lexer.TTRep) {
+	switch {
+	case p.found(lexer.TTRep):
 		end.T(matcher.NewShortcut(), start)
+	case p.found(lexer.TTRepRange):
+		min, max := p.repRange()
+		if single != nil {
+			p.bounds = append(p.bounds, Bound{Container: single, Min: min, Max: max})
+		}
+		if p.relaxed {
+			min, max = 0, -1
+		}
+		start, end = repeat(start, end, min, max)
 	}
 	return start, end
 }
 
+// repRange parses the matched {min,max} token
+func (p *parser) repRange() (int, int) {
+	val := strings.Trim(p.matchedToken.Val, "{}")
+	bounds := strings.SplitN(val, ",", 2)
+	min, err := strconv.Atoi(bounds[0])
+	if err != nil {
+		p.back()
+		panic("Invalid repetition count")
+	}
+	max := min
+	if len(bounds) == 2 {
+		max = -1
+		if bounds[1] != "" {
+			max, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				p.back()
+				panic("Invalid repetition count")
+			}
+		}
+	}
+	switch {
+	case max == 0:
+		p.back()
+		panic("Invalid repetition: the maximum count should be at least 1")
+	case max >= 0 && max < min:
+		p.back()
+		panic("Invalid repetition: the minimum count is greater than the maximum")
+	}
+	return min, max
+}
+
+// repeat builds an FSM matching between min and max (-1 for unbounded) occurrences of the one going from start to end:
+// X{2,4} is built as X X [X [X]], X{2,} as X X...
+func repeat(start, end *fsm.State, min, max int) (*fsm.State, *fsm.State) {
+	rstart := fsm.NewState()
+	rend := rstart
+	appendCopy := func() (*fsm.State, *fsm.State) {
+		s, e := clone(start, end)
+		rend.T(matcher.NewShortcut(), s)
+		rend = e
+		return s, e
+	}
+
+	for i := 0; i < min; i++ {
+		s, e := appendCopy()
+		if max < 0 && i == min-1 {
+			e.T(matcher.NewShortcut(), s)
+		}
+	}
+
+	switch {
+	case max < 0 && min == 0:
+		skip := rend
+		s, e := appendCopy()
+		e.T(matcher.NewShortcut(), s)
+		skip.T(matcher.NewShortcut(), e)
+	case max > min:
+		final := fsm.NewState()
+		for i := min; i < max; i++ {
+			rend.T(matcher.NewShortcut(), final)
+			appendCopy()
+		}
+		rend.T(matcher.NewShortcut(), final)
+		rend = final
+	}
+	return rstart, rend
+}
+
+// clone copies the states reachable from start, returning the copies of start and end
+func clone(start, end *fsm.State) (*fsm.State, *fsm.State) {
+	copies := map[*fsm.State]*fsm.State{}
+	var cp func(s *fsm.State) *fsm.State
+	cp = func(s *fsm.State) *fsm.State {
+		if c, ok := copies[s]; ok {
+			return c
+		}
+		c := fsm.NewState()
+		c.Terminal = s.Terminal
+		copies[s] = c
+		for _, tr := range s.Transitions {
+			c.T(tr.Matcher, cp(tr.Next))
+		}
+		return c
+	}
+	return cp(start), cp(end)
+}
+
+// arg creates the matcher of the argument a, which accepts negative numbers if a is numeric and no digit option
+// like -1 is declared
+func (p *parser) arg(a *container.Container) matcher.Matcher {
+	if values.IsNumeric(a.Value) && !matcher.HasDigitOptions(p.optionsIdx) {
+		return matcher.NewNumberArg(a)
+	}
+	return matcher.NewArg(a)
+}
+
+// literal returns the container of a keyword, creating it on its first use
+func (p *parser) literal(word string) *container.Container {
+	if lit, ok := p.literalsIdx[word]; ok {
+		return lit
+	}
+	lit := &container.Container{Name: word, Value: values.NewBool(new(bool), false)}
+	if p.literalsIdx != nil {
+		p.literalsIdx[word] = lit
+	}
+	return lit
+}
+
+// placeholder stores the =<value> token following an option, if any, in the option's container
+func (p *parser) placeholder(opt *container.Container) {
+	if !p.found(lexer.TTOptValue) {
+		return
+	}
+	if opt.Placeholder == "" {
+		opt.Placeholder = strings.TrimPrefix(p.matchedToken.Val, "=")
+	}
+}
+
 func (p *parser) canAtom() bool {
 	switch {
 	case p.is(lexer.TTArg):
 		return true
+	case p.is(lexer.TTLiteral):
+		return true
 	case p.is(lexer.TTOptions):
 		return true
 	case p.is(lexer.TTShortOpt):
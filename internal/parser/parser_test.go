@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"testing"
 
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"github.com/duanqy/cli/internal/container"
 	"github.com/duanqy/cli/internal/fsm/fsmtest"
 	"github.com/duanqy/cli/internal/lexer"
+	"github.com/duanqy/cli/internal/values"
 	"github.com/stretchr/testify/require"
 )
 
@@ -146,6 +148,86 @@ func TestParse(t *testing.T) {
 						(S1) -ab (S2)
 			`,
 		},
+		{
+			spec: "ARG{1}",
+			expectedFsm: `
+						S1 ARG (S2)
+			`,
+		},
+		{
+			spec: "ARG{2}",
+			expectedFsm: `
+						S1 ARG S2
+						S2 ARG (S3)
+			`,
+		},
+		{
+			spec: "ARG{1,3}",
+			expectedFsm: `
+						S1 ARG (S2)
+						(S2) ARG (S3)
+						(S3) ARG (S4)
+			`,
+		},
+		{
+			spec: "-a{0,2}",
+			expectedFsm: `
+						(S1) -a (S2)
+						(S2) -a (S3)
+			`,
+		},
+		{
+			spec: "ARG{2,}",
+			expectedFsm: `
+						S1 ARG S2
+						S2 ARG (S3)
+						(S3) ARG (S3)
+			`,
+		},
+		{
+			spec: "ARG{0,}",
+			expectedFsm: `
+						(S1) ARG (S2)
+						(S2) ARG (S2)
+			`,
+		},
+		{
+			spec: "(-a ARG){2}",
+			expectedFsm: `
+						S1 -a S2
+						S2 ARG S3
+						S3 -a S4
+						S4 ARG (S5)
+			`,
+		},
+		{
+			spec: "service (start | stop) ARG",
+			expectedFsm: `
+						S1 service S2
+						S2 start S3
+						S2 stop S5
+						S3 ARG (S4)
+						S5 ARG (S4)
+			`,
+		},
+		{
+			spec: "[-a] [dry-run] ARG",
+			expectedFsm: `
+						S1 -a S2
+						S1 ARG (S4)
+						S1 dry-run S3
+						S2 ARG (S4)
+						S2 dry-run S3
+						S3 ARG (S4)
+			`,
+		},
+		{
+			spec: "[ARG{2}]",
+			expectedFsm: `
+						(S1) ARG S2
+						S2 ARG (S3)
+			`,
+		},
 	}
 
 	for _, cas := range cases {
@@ -242,6 +324,26 @@ func TestParseErrors(t *testing.T) {
 			msg:  "Was expecting ClosePar",
 			pos:  4,
 		},
+		{
+			spec: "ARG{0}",
+			msg:  "Invalid repetition",
+			pos:  3,
+		},
+		{
+			spec: "ARG{3,2}",
+			msg:  "Invalid repetition",
+			pos:  3,
+		},
+		{
+			spec: "ARG...{2}",
+			msg:  "Unexpected input",
+			pos:  6,
+		},
+		{
+			spec: "-- {2}",
+			msg:  "Unexpected input",
+			pos:  3,
+		},
 	}
 
 	for _, cas := range cases {
@@ -285,3 +387,140 @@ func cleanFsmStr(s string) string {
 	//fmt.Printf("%#v\n", lines)
 	return strings.Join(clines, "\n")
 }
+
+func TestParseLiterals(t *testing.T) {
+	spec := "(start | stop) ARG | stop"
+	tokens, err := lexer.Tokenize(spec)
+	require.NoError(t, err)
+
+	idx := map[string]*container.Container{}
+	_, err = Parse(tokens, Params{
+		Spec:        spec,
+		Args:        []*container.Container{argCon},
+		ArgsIdx:     argsIndex,
+		LiteralsIdx: idx,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, idx, 2)
+	require.Equal(t, "start", idx["start"].Name)
+	require.Equal(t, "stop", idx["stop"].Name)
+}
+
+func TestParseSingleDashLongOptions(t *testing.T) {
+	params := Params{
+		Options:    []*container.Container{optACon, optBCon},
+		OptionsIdx: optsIndex,
+	}
+
+	cases := []struct {
+		spec        string
+		expectedFsm string
+	}{
+		{"-all", "S1 -a (S2)"},
+		{"[-ball] -ab", "S1 -ab (S3)\nS1 -b S2\nS2 -ab (S3)"},
+	}
+	for _, cas := range cases {
+		t.Run(cas.spec, func(t *testing.T) {
+			tokens, err := lexer.Tokenize(cas.spec)
+			require.NoError(t, err)
+
+			params.Spec = cas.spec
+			params.SingleDashLongOptions = false
+			_, err = Parse(tokens, params)
+			require.Error(t, err, "single dash long options should be rejected by default")
+
+			params.SingleDashLongOptions = true
+			s, err := Parse(tokens, params)
+			require.NoError(t, err)
+			require.Equal(t, cas.expectedFsm, fsmtest.FsmStr(s))
+		})
+	}
+}
+
+func TestParseNegativeNumberArgs(t *testing.T) {
+	n := &container.Container{Name: "N", Value: values.NewInt(new(int), 0)}
+	one := &container.Container{Name: "1", Names: []string{"-1"}, Value: values.NewBool(new(bool), false)}
+	tokens, err := lexer.Tokenize("N")
+	require.NoError(t, err)
+
+	params := Params{Spec: "N", Args: []*container.Container{n}, ArgsIdx: map[string]*container.Container{"N": n}}
+	s, err := Parse(tokens, params)
+	require.NoError(t, err)
+	_, ok := s.Match([]string{"-3"})
+	require.True(t, ok, "a numeric argument should accept negative numbers")
+
+	params.Options = []*container.Container{one}
+	params.OptionsIdx = map[string]*container.Container{"-1": one}
+	s, err = Parse(tokens, params)
+	require.NoError(t, err)
+	_, ok = s.Match([]string{"-3"})
+	require.False(t, ok, "negative numbers should be read as options when a digit option is declared")
+}
+
+func TestParsePlaceholders(t *testing.T) {
+	var (
+		optF = &container.Container{Name: "f file", Names: []string{"-f", "--file"}}
+		optO = &container.Container{Name: "o", Names: []string{"-o"}}
+		optX = &container.Container{Name: "x", Names: []string{"-x"}}
+	)
+	spec := "--file=<path> [-o=<out>] [-f=<other>] -x"
+	tokens, err := lexer.Tokenize(spec)
+	require.NoError(t, err)
+
+	_, err = Parse(tokens, Params{
+		Spec:       spec,
+		Options:    []*container.Container{optF, optO, optX},
+		OptionsIdx: map[string]*container.Container{"-f": optF, "--file": optF, "-o": optO, "-x": optX},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "<path>", optF.Placeholder, "the first placeholder should be kept")
+	require.Equal(t, "<out>", optO.Placeholder)
+	require.Equal(t, "", optX.Placeholder)
+}
+
+func TestExplain(t *testing.T) {
+	var (
+		point = &container.Container{Name: "POINT"}
+		name  = &container.Container{Name: "NAME"}
+		optI  = &container.Container{Name: "I", Names: []string{"-I"}, Value: values.NewStrings(new([]string), nil)}
+	)
+	params := Params{
+		Args:       []*container.Container{point, name},
+		ArgsIdx:    map[string]*container.Container{"POINT": point, "NAME": name},
+		Options:    []*container.Container{optI},
+		OptionsIdx: map[string]*container.Container{"-I": optI},
+	}
+
+	cases := []struct {
+		spec     string
+		args     []string
+		expected string
+	}{
+		{"POINT{2,4}", []string{"a"}, "expected at least 2 POINT, got 1"},
+		{"POINT{2,4}", []string{"a", "b", "c", "d", "e"}, "expected at most 4 POINT, got 5"},
+		{"POINT{2}", []string{"a", "b", "c"}, "expected 2 POINT, got 3"},
+		{"POINT{2} NAME", []string{"a"}, "expected 2 POINT, got 0"},
+		{"POINT{2,}", []string{"a"}, "expected at least 2 POINT, got 1"},
+		{"-I{0,2} NAME", []string{"-I", "a", "-I", "b", "-I", "c", "x"}, "expected at most 2 -I, got 3"},
+		{"POINT{2,4}", []string{"a", "b"}, ""},
+		{"POINT NAME", []string{"a"}, ""},
+		{"POINT{2,4}", []string{"-x"}, ""},
+	}
+
+	for _, cas := range cases {
+		t.Run(fmt.Sprintf("%s %v", cas.spec, cas.args), func(t *testing.T) {
+			tokens, err := lexer.Tokenize(cas.spec)
+			require.NoError(t, err)
+			params.Spec = cas.spec
+
+			err = Explain(tokens, params, cas.args)
+			if cas.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, cas.expected)
+		})
+	}
+}
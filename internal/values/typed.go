@@ -0,0 +1,137 @@
+package values
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/******************************************************************************/
+/* TYPED                                                                      */
+/******************************************************************************/
+
+// TypedValue is a flag.Value type holding values of an arbitrary type, converted from and to strings
+// using user supplied functions
+type TypedValue[T any] struct {
+	into   *T
+	parse  func(string) (T, error)
+	format func(T) string
+}
+
+var (
+	_ flag.Value    = NewTyped(new(int), nil, nil, 0)
+	_ DefaultValued = NewTyped(new(int), nil, nil, 0)
+	_ Placeholdered = NewTyped(new(int), nil, nil, 0)
+)
+
+// NewTyped creates a new typed value.
+// format may be nil, in which case the value is rendered using fmt.Sprint
+func NewTyped[T any](into *T, parse func(string) (T, error), format func(T) string, v T) *TypedValue[T] {
+	*into = v
+	return &TypedValue[T]{into: into, parse: parse, format: format}
+}
+
+// Set sets the value from a provided string
+func (tv *TypedValue[T]) Set(s string) error {
+	v, err := tv.parse(s)
+	if err != nil {
+		return err
+	}
+	*tv.into = v
+	return nil
+}
+
+func (tv *TypedValue[T]) String() string {
+	return formatTyped(tv.format, *tv.into)
+}
+
+// Placeholder returns the lowercased name of T, e.g. <url> for url.URL
+func (tv *TypedValue[T]) Placeholder() string {
+	return typePlaceholder(reflect.TypeOf(tv.into).Elem())
+}
+
+// IsDefault return true if the stored value is the zero value of T
+func (tv *TypedValue[T]) IsDefault() bool {
+	return reflect.ValueOf(tv.into).Elem().IsZero()
+}
+
+/******************************************************************************/
+/* TYPED SLICE                                                                */
+/******************************************************************************/
+
+// TypedSliceValue is a flag.Value type holding slices of an arbitrary type, converted from and to strings
+// using user supplied functions
+type TypedSliceValue[T any] struct {
+	into   *[]T
+	parse  func(string) (T, error)
+	format func(T) string
+}
+
+var (
+	_ flag.Value    = NewTypedSlice(new([]int), nil, nil, nil)
+	_ MultiValued   = NewTypedSlice(new([]int), nil, nil, nil)
+	_ DefaultValued = NewTypedSlice(new([]int), nil, nil, nil)
+	_ Placeholdered = NewTypedSlice(new([]int), nil, nil, nil)
+)
+
+// NewTypedSlice creates a new multi-valued typed value.
+// format may be nil, in which case the values are rendered using fmt.Sprint
+func NewTypedSlice[T any](into *[]T, parse func(string) (T, error), format func(T) string, v []T) *TypedSliceValue[T] {
+	*into = v
+	return &TypedSliceValue[T]{into: into, parse: parse, format: format}
+}
+
+// Set parses the provided string and appends it to the slice
+func (ts *TypedSliceValue[T]) Set(s string) error {
+	v, err := ts.parse(s)
+	if err != nil {
+		return err
+	}
+	*ts.into = append(*ts.into, v)
+	return nil
+}
+
+func (ts *TypedSliceValue[T]) String() string {
+	res := "["
+	for idx, v := range *ts.into {
+		if idx > 0 {
+			res += ", "
+		}
+		res += formatTyped(ts.format, v)
+	}
+	return res + "]"
+}
+
+// Clear clears the slice
+func (ts *TypedSliceValue[T]) Clear() {
+	*ts.into = nil
+}
+
+// Placeholder returns the lowercased name of T, e.g. <url> for url.URL
+func (ts *TypedSliceValue[T]) Placeholder() string {
+	return typePlaceholder(reflect.TypeOf(ts.into).Elem().Elem())
+}
+
+// IsDefault return true if the slice is empty
+func (ts *TypedSliceValue[T]) IsDefault() bool {
+	return len(*ts.into) == 0
+}
+
+func typePlaceholder(t reflect.Type) string {
+	for t.Name() == "" && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	name := t.Name()
+	if name == "" {
+		name = "value"
+	}
+	return "<" + strings.ToLower(name) + ">"
+}
+
+func formatTyped[T any](format func(T) string, v T) string {
+	if format == nil {
+		return fmt.Sprint(v)
+	}
+	return format(v)
+}
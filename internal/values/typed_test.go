@@ -0,0 +1,103 @@
+package values
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseURL(s string) (url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return url.URL{}, err
+	}
+	if u.Scheme == "" {
+		return url.URL{}, errors.New("missing scheme")
+	}
+	return *u, nil
+}
+
+func formatURL(u url.URL) string {
+	return u.String()
+}
+
+func TestTypedParam(t *testing.T) {
+	var into url.URL
+
+	param := NewTyped(&into, parseURL, formatURL, url.URL{})
+	require.True(t, param.IsDefault())
+	require.False(t, IsBool(param))
+
+	cases := []struct {
+		input  string
+		err    bool
+		string string
+	}{
+		{"http://example.com", false, "http://example.com"},
+		{"https://example.com/a?b=c", false, "https://example.com/a?b=c"},
+		{"example.com", true, ""},
+	}
+
+	for _, cas := range cases {
+		t.Logf("testing .Set() with %q", cas.input)
+
+		err := param.Set(cas.input)
+
+		if cas.err {
+			require.Errorf(t, err, "value %q should have returned an error", cas.input)
+			continue
+		}
+
+		require.NoError(t, err)
+		require.Equal(t, cas.string, into.String())
+		require.Equal(t, cas.string, param.String())
+		require.False(t, param.IsDefault())
+	}
+}
+
+func TestTypedParamDefaultFormat(t *testing.T) {
+	var into int
+	param := NewTyped(&into, func(s string) (int, error) { return len(s), nil }, nil, 3)
+
+	require.Equal(t, 3, into)
+	require.Equal(t, "3", param.String())
+	require.False(t, param.IsDefault())
+
+	require.NoError(t, param.Set(""))
+	require.Equal(t, 0, into)
+	require.True(t, param.IsDefault())
+}
+
+func TestTypedSliceParam(t *testing.T) {
+	var into []string
+
+	param := NewTypedSlice(&into, func(s string) (string, error) { return strings.ToUpper(s), nil }, func(s string) string { return "<" + s + ">" }, []string{"DEF"})
+	require.False(t, param.IsDefault())
+	require.Equal(t, "[<DEF>]", param.String())
+
+	param.Clear()
+	require.Nil(t, into)
+	require.True(t, param.IsDefault())
+
+	require.NoError(t, param.Set("a"))
+	require.NoError(t, param.Set("b"))
+	require.Equal(t, []string{"A", "B"}, into)
+	require.Equal(t, "[<A>, <B>]", param.String())
+}
+
+func TestTypedSetFromEnv(t *testing.T) {
+	var into []url.URL
+	param := NewTypedSlice(&into, parseURL, formatURL, nil)
+
+	os.Setenv("A", "http://a.com, https://b.com")
+	require.True(t, SetFromEnv(param, "A"))
+	require.Equal(t, "[http://a.com, https://b.com]", param.String())
+
+	os.Setenv("A", "http://a.com, b.com")
+	require.False(t, SetFromEnv(param, "A"))
+	require.Empty(t, into)
+}
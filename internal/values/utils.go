@@ -2,6 +2,7 @@ package values
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"strings"
 )
@@ -15,7 +16,44 @@ func IsBool(v flag.Value) bool {
 	return false
 }
 
-// SetFromEnv fills a value from a list of env vars
+// IsNumeric checks if a given value holds numbers, i.e. implements the NumericValued interface
+func IsNumeric(v flag.Value) bool {
+	if nv, ok := v.(NumericValued); ok {
+		return nv.IsNumeric()
+	}
+
+	return false
+}
+
+// Placeholdered is an interface values can implement to provide the placeholder describing them in help messages
+type Placeholdered interface {
+	// Placeholder returns the placeholder of the value, e.g. <file>
+	Placeholder() string
+}
+
+// Placeholder returns the default placeholder describing the value in help messages, e.g. <int>, or an empty string
+// for bool values which do not take one
+func Placeholder(v flag.Value) string {
+	if IsBool(v) {
+		return ""
+	}
+	switch v.(type) {
+	case *IntValue, *Int64Value, *IntsValue:
+		return "<int>"
+	case *UintValue, *Uint64Value:
+		return "<uint>"
+	case *Float64Value, *Floats64Value:
+		return "<float>"
+	case *DurationValue:
+		return "<duration>"
+	case Placeholdered:
+		return v.(Placeholdered).Placeholder()
+	default:
+		return "<string>"
+	}
+}
+
+// SetFromEnv fills a value from a list of env vars, multi-valued values being split on commas
 func SetFromEnv(into flag.Value, envVars string) bool {
 	multiValued, isMulti := into.(MultiValued)
 
@@ -33,6 +71,9 @@ func SetFromEnv(into flag.Value, envVars string) bool {
 			}
 
 			vs := strings.Split(v, ",")
+			for i := range vs {
+				vs[i] = strings.TrimSpace(vs[i])
+			}
 			if err := setMultivalued(multiValued, vs); err == nil {
 				return true
 			}
@@ -41,11 +82,41 @@ func SetFromEnv(into flag.Value, envVars string) bool {
 	return false
 }
 
+// SetFromEnvDelimited fills a value from a list of env vars, multi-valued values being split on sep with Split.
+//
+// An error is returned if an env var value cannot be split, e.g. because of an unterminated quote.
+func SetFromEnvDelimited(into flag.Value, envVars string, sep string) (bool, error) {
+	multiValued, isMulti := into.(MultiValued)
+
+	if len(envVars) > 0 {
+		for _, ev := range strings.Fields(envVars) {
+			v := os.Getenv(ev)
+			if len(v) == 0 {
+				continue
+			}
+			if !isMulti {
+				if err := into.Set(v); err == nil {
+					return true, nil
+				}
+				continue
+			}
+
+			vs, err := Split(v, sep)
+			if err != nil {
+				return false, fmt.Errorf("invalid value for $%s: %v", ev, err)
+			}
+			if err := setMultivalued(multiValued, vs); err == nil {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 func setMultivalued(into MultiValued, values []string) error {
 	into.Clear()
 
 	for _, v := range values {
-		v = strings.TrimSpace(v)
 		if err := into.Set(v); err != nil {
 			into.Clear()
 			return err
@@ -54,3 +125,75 @@ func setMultivalued(into MultiValued, values []string) error {
 
 	return nil
 }
+
+/*
+Split splits s on every occurrence of sep, honouring quoting and escaping:
+
+- a part can be enclosed in single or double quotes, in which case sep is not interpreted inside it
+- a backslash escapes sep, a quote or a backslash, e.g. a\,b yields a single a,b part
+
+Unquoted leading and trailing whitespace is removed from every part.
+*/
+func Split(s, sep string) ([]string, error) {
+	var (
+		res   []string
+		buf   []byte
+		keep  int
+		quote byte
+	)
+	emit := func() {
+		res = append(res, string(buf[:keep]))
+		buf, keep = buf[:0], 0
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			switch {
+			case c == quote:
+				quote = 0
+			case c == '\\' && i+1 < len(s) && (s[i+1] == quote || s[i+1] == '\\'):
+				i++
+				buf = append(buf, s[i])
+			default:
+				buf = append(buf, c)
+			}
+			keep = len(buf)
+		case sep != "" && strings.HasPrefix(s[i:], sep):
+			emit()
+			i += len(sep) - 1
+		case c == '\\' && i+1 < len(s) && escapable(s[i+1:], sep):
+			i++
+			buf = append(buf, s[i])
+			keep = len(buf)
+		case c == '"' || c == '\'':
+			quote = c
+			keep = len(buf)
+		case isSpace(c):
+			if len(buf) > 0 {
+				buf = append(buf, c)
+			}
+		default:
+			buf = append(buf, c)
+			keep = len(buf)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	}
+	emit()
+	return res, nil
+}
+
+func escapable(s, sep string) bool {
+	switch s[0] {
+	case '\\', '"', '\'':
+		return true
+	}
+	return sep != "" && strings.HasPrefix(s, sep)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
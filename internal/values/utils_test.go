@@ -1,8 +1,12 @@
 package values
 
 import (
+	"fmt"
+	"net"
+	"net/url"
 	"reflect"
 	"testing"
+	"time"
 
 	"flag"
 
@@ -20,6 +24,19 @@ func TestIsBool(t *testing.T) {
 	require.False(t, IsBool(NewInts(new([]int), nil)))
 }
 
+func TestIsNumeric(t *testing.T) {
+	require.True(t, IsNumeric(NewInt(new(int), 0)))
+	require.True(t, IsNumeric(NewInt64(new(int64), 0)))
+	require.True(t, IsNumeric(NewFloat64(new(float64), 0)))
+	require.True(t, IsNumeric(NewInts(new([]int), nil)))
+	require.True(t, IsNumeric(NewFloats64(new([]float64), nil)))
+
+	require.False(t, IsNumeric(NewBool(new(bool), false)))
+	require.False(t, IsNumeric(NewString(new(string), "")))
+	require.False(t, IsNumeric(NewUint(new(uint), 0)))
+	require.False(t, IsNumeric(NewStrings(new([]string), nil)))
+}
+
 func TestSetFromEnv(t *testing.T) {
 	cases := []struct {
 		desc     string
@@ -213,3 +230,94 @@ func TestSetFromEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestSplit(t *testing.T) {
+	cases := []struct {
+		input    string
+		sep      string
+		expected []string
+		err      bool
+	}{
+		{input: "a", sep: ",", expected: []string{"a"}},
+		{input: "a,b,c", sep: ",", expected: []string{"a", "b", "c"}},
+		{input: " a , b ,c ", sep: ",", expected: []string{"a", "b", "c"}},
+		{input: "a,,c,", sep: ",", expected: []string{"a", "", "c", ""}},
+		{input: "/bin:/usr/bin", sep: ":", expected: []string{"/bin", "/usr/bin"}},
+		{input: "a b\nc\n", sep: "\n", expected: []string{"a b", "c", ""}},
+		{input: "a::b", sep: "::", expected: []string{"a", "b"}},
+		{input: `a\,b,c`, sep: ",", expected: []string{"a,b", "c"}},
+		{input: `a\\,b`, sep: ",", expected: []string{`a\`, "b"}},
+		{input: `C:\dir,D:\dir`, sep: ",", expected: []string{`C:\dir`, `D:\dir`}},
+		{input: `"a,b",c`, sep: ",", expected: []string{"a,b", "c"}},
+		{input: `'a,b' , " c "`, sep: ",", expected: []string{"a,b", " c "}},
+		{input: `"say \"hi\"",x`, sep: ",", expected: []string{`say "hi"`, "x"}},
+		{input: `pre"a,b"post`, sep: ",", expected: []string{"prea,bpost"}},
+		{input: `"a,b`, sep: ",", err: true},
+		{input: `'a`, sep: ",", err: true},
+	}
+
+	for _, cas := range cases {
+		t.Run(cas.input, func(t *testing.T) {
+			actual, err := Split(cas.input, cas.sep)
+			if cas.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, cas.expected, actual)
+		})
+	}
+}
+
+func TestSetFromEnvDelimited(t *testing.T) {
+	var into []string
+	os.Setenv("A", "/bin:/usr/bin:'/my:dir'")
+
+	ok, err := SetFromEnvDelimited(NewStrings(&into, nil), "A", ":")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []string{"/bin", "/usr/bin", "/my:dir"}, into)
+
+	os.Setenv("A", "'/bin")
+	ok, err = SetFromEnvDelimited(NewStrings(&into, []string{"x"}), "A", ":")
+	require.EqualError(t, err, `invalid value for $A: unterminated ' quote in "'/bin"`)
+	require.False(t, ok)
+	require.Equal(t, []string{"x"}, into)
+}
+
+func TestSetFromEnvIgnoresQuotes(t *testing.T) {
+	var into []string
+	os.Setenv("A", "O'Brien, Smith")
+
+	require.True(t, SetFromEnv(NewStrings(&into, nil), "A"))
+	require.Equal(t, []string{"O'Brien", "Smith"}, into)
+}
+
+func TestPlaceholder(t *testing.T) {
+	cases := []struct {
+		value    flag.Value
+		expected string
+	}{
+		{NewBool(new(bool), false), ""},
+		{NewString(new(string), ""), "<string>"},
+		{NewStrings(new([]string), nil), "<string>"},
+		{NewInt(new(int), 0), "<int>"},
+		{NewInts(new([]int), nil), "<int>"},
+		{NewInt64(new(int64), 0), "<int>"},
+		{NewUint(new(uint), 0), "<uint>"},
+		{NewUint64(new(uint64), 0), "<uint>"},
+		{NewFloat64(new(float64), 0), "<float>"},
+		{NewFloats64(new([]float64), nil), "<float>"},
+		{NewDuration(new(time.Duration), 0), "<duration>"},
+		{NewTyped(new(url.URL), nil, nil, url.URL{}), "<url>"},
+		{NewTyped(new(*url.URL), nil, nil, nil), "<url>"},
+		{NewTypedSlice(new([]net.IP), nil, nil, nil), "<ip>"},
+		{NewTyped(new(struct{}), nil, nil, struct{}{}), "<value>"},
+	}
+
+	for _, cas := range cases {
+		t.Run(fmt.Sprintf("%T", cas.value), func(t *testing.T) {
+			require.Equal(t, cas.expected, Placeholder(cas.value))
+		})
+	}
+}
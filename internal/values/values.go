@@ -21,6 +21,14 @@ type MultiValued interface {
 	Clear()
 }
 
+// NumericValued is an interface values can implement to indicate that they hold numbers, so that negative numbers
+// like -5 can be passed to them without being mistaken for options
+type NumericValued interface {
+	flag.Value
+	// IsNumeric should return true to indicate that this value holds numbers
+	IsNumeric() bool
+}
+
 // DefaultValued in an interface to determine if the value stored is the default value, and thus does not need be shown in the help message
 type DefaultValued interface {
 	// IsDefault should return true if the value stored is the default value, and thus does not need be shown in the help message
@@ -143,7 +151,8 @@ func (sa *StringValue) IsDefault() bool {
 type IntValue int
 
 var (
-	_ flag.Value = NewInt(new(int), 0)
+	_ flag.Value    = NewInt(new(int), 0)
+	_ NumericValued = NewInt(new(int), 0)
 )
 
 // NewInt creates a new int value
@@ -166,6 +175,11 @@ func (ia *IntValue) String() string {
 	return fmt.Sprintf("%v", *ia)
 }
 
+// IsNumeric returns true
+func (ia *IntValue) IsNumeric() bool {
+	return true
+}
+
 
 /******************************************************************************/
 /* INT64                                                                      */
@@ -175,7 +189,8 @@ func (ia *IntValue) String() string {
 type Int64Value int64
 
 var (
-	_ flag.Value = NewInt64(new(int64), 0)
+	_ flag.Value    = NewInt64(new(int64), 0)
+	_ NumericValued = NewInt64(new(int64), 0)
 )
 
 // NewInt creates a new int value
@@ -198,6 +213,11 @@ func (ia *Int64Value) String() string {
 	return fmt.Sprintf("%v", *ia)
 }
 
+// IsNumeric returns true
+func (ia *Int64Value) IsNumeric() bool {
+	return true
+}
+
 /******************************************************************************/
 /* UINT                                                                       */
 /******************************************************************************/
@@ -268,7 +288,8 @@ func (uv *Uint64Value) String() string {
 type Float64Value float64
 
 var (
-	_ flag.Value = NewFloat64(new(float64), 0)
+	_ flag.Value    = NewFloat64(new(float64), 0)
+	_ NumericValued = NewFloat64(new(float64), 0)
 )
 
 // NewFloat64 creates a new int value
@@ -291,6 +312,11 @@ func (ia *Float64Value) String() string {
 	return fmt.Sprintf("%v", *ia)
 }
 
+// IsNumeric returns true
+func (ia *Float64Value) IsNumeric() bool {
+	return true
+}
+
 /******************************************************************************/
 /* STRINGS                                                                    */
 /******************************************************************************/
@@ -348,6 +374,7 @@ var (
 	_ flag.Value    = NewInts(new([]int), nil)
 	_ MultiValued   = NewInts(new([]int), nil)
 	_ DefaultValued = NewInts(new([]int), nil)
+	_ NumericValued = NewInts(new([]int), nil)
 )
 
 // NewInts creates a new multi-int value
@@ -377,6 +404,11 @@ func (ia *IntsValue) String() string {
 	return res + "]"
 }
 
+// IsNumeric returns true
+func (ia *IntsValue) IsNumeric() bool {
+	return true
+}
+
 // Clear clears the slice
 func (ia *IntsValue) Clear() {
 	*ia = nil
@@ -398,6 +430,7 @@ var (
 	_ flag.Value    = NewFloats64(new([]float64), nil)
 	_ MultiValued   = NewFloats64(new([]float64), nil)
 	_ DefaultValued = NewFloats64(new([]float64), nil)
+	_ NumericValued = NewFloats64(new([]float64), nil)
 )
 
 // NewFloats64 creates a new multi-int value
@@ -427,6 +460,11 @@ func (ia *Floats64Value) String() string {
 	return res + "]"
 }
 
+// IsNumeric returns true
+func (ia *Floats64Value) IsNumeric() bool {
+	return true
+}
+
 // Clear clears the slice
 func (ia *Floats64Value) Clear() {
 	*ia = nil
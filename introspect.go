@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/duanqy/cli/internal/values"
+)
+
+// OptionInfo is a read-only description of an option, as returned by Cmd.Options
+type OptionInfo struct {
+	// Names are the option names with their dashes, e.g. -f and --force
+	Names []string
+	Desc  string
+	// EnvVar is the env var the option is read from, if any
+	EnvVar string
+	// Default is the option value before the env vars and the command line are parsed, rendered as in the help,
+	// e.g. "eu" with the quotes. It is empty for the zero value
+	Default string
+	// Type is the kind of value the option accepts, e.g. bool, string or int
+	Type string
+	// MultiValued is true if the option can be repeated to accumulate values
+	MultiValued bool
+	Hidden      bool
+	// Persistent is true if the option can also be used after the names of the sub commands
+	Persistent bool
+	// Required is true if the command spec can not be satisfied without the option
+	Required bool
+}
+
+// ArgumentInfo is a read-only description of a positional argument, as returned by Cmd.Arguments
+type ArgumentInfo struct {
+	// Name is the argument name, e.g. SRC
+	Name string
+	Desc string
+	// EnvVar is the env var the argument is read from, if any
+	EnvVar string
+	// Default is the argument value before the env vars and the command line are parsed, rendered as in the help,
+	// e.g. "eu" with the quotes. It is empty for the zero value
+	Default string
+	// Type is the kind of value the argument accepts, e.g. string or int
+	Type string
+	// MultiValued is true if the argument can be repeated to accumulate values
+	MultiValued bool
+	Hidden      bool
+	// Required is true if the command spec can not be satisfied without the argument
+	Required bool
+}
+
+// Name returns the command name, i.e. its first alias
+func (c *Cmd) Name() string {
+	return c.name
+}
+
+// Aliases returns all the names of the command
+func (c *Cmd) Aliases() []string {
+	return append([]string{}, c.aliases...)
+}
+
+// Description returns the command short description
+func (c *Cmd) Description() string {
+	return c.desc
+}
+
+// Path returns the command full path, e.g. "app deploy"
+func (c *Cmd) Path() string {
+	return c.fullPath()
+}
+
+// Parent returns the command c is a sub command of, or nil for the app
+func (c *Cmd) Parent() *Cmd {
+	return c.parent
+}
+
+// Subcommands returns the sub commands of c, initialized
+func (c *Cmd) Subcommands() []*Cmd {
+	res := append([]*Cmd{}, c.commands...)
+	for _, sub := range res {
+		_ = sub.doInit()
+	}
+	return res
+}
+
+// Hidden tells whether c is left out of the help and of the suggestions
+func (c *Cmd) Hidden() bool {
+	return c.hidden
+}
+
+// Deprecated tells whether c is deprecated, with its replacement if any
+func (c *Cmd) Deprecated() (bool, string) {
+	return c.deprecated, c.replacement
+}
+
+// RenamedTo returns the new name of c if c is the old name of a renamed command, or an empty string
+func (c *Cmd) RenamedTo() string {
+	return c.renamedTo
+}
+
+// Category returns the section of the help of its parent c is listed in, or an empty string for the Commands section
+func (c *Cmd) Category() string {
+	return c.category
+}
+
+// Options describes the options of c
+func (c *Cmd) Options() []OptionInfo {
+	_ = c.doInit()
+	res := make([]OptionInfo, 0, len(c.options))
+	for _, opt := range c.options {
+		res = append(res, OptionInfo{
+			Names:       append([]string{}, opt.Names...),
+			Desc:        opt.Desc,
+			EnvVar:      opt.EnvVar,
+			Default:     defaultOf(opt),
+			Type:        typeOf(opt),
+			MultiValued: isMultiValued(opt),
+			Hidden:      opt.Hidden,
+			Persistent:  opt.Persistent,
+			Required:    c.requires(opt),
+		})
+	}
+	return res
+}
+
+// Arguments describes the positional arguments of c
+func (c *Cmd) Arguments() []ArgumentInfo {
+	_ = c.doInit()
+	res := make([]ArgumentInfo, 0, len(c.args))
+	for _, arg := range c.args {
+		res = append(res, ArgumentInfo{
+			Name:        arg.Name,
+			Desc:        arg.Desc,
+			EnvVar:      arg.EnvVar,
+			Default:     defaultOf(arg),
+			Type:        typeOf(arg),
+			MultiValued: isMultiValued(arg),
+			Hidden:      arg.Hidden,
+			Required:    c.requires(arg),
+		})
+	}
+	return res
+}
+
+// Walk calls fn for c and all its sub commands, recursively, parents first.
+// It stops at the first error returned by fn and returns it
+func (c *Cmd) Walk(fn func(*Cmd) error) error {
+	_ = c.doInit()
+	if err := fn(c); err != nil {
+		return err
+	}
+	for _, sub := range c.commands {
+		if err := sub.Walk(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordDefaults keeps the values the parameters were declared with, before they are overridden by env vars
+func (c *Cmd) recordDefaults() {
+	for _, con := range append(append([]*container.Container{}, c.options...), c.args...) {
+		if dv, ok := con.Value.(values.DefaultValued); ok && dv.IsDefault() {
+			continue
+		}
+		con.Default = con.Value.String()
+	}
+}
+
+func (c *Cmd) requires(con *container.Container) bool {
+	return c.fsm != nil && c.fsm.Requires(con)
+}
+
+func defaultOf(con *container.Container) string {
+	if s, ok := con.Default.(string); ok {
+		return s
+	}
+	return ""
+}
+
+func typeOf(con *container.Container) string {
+	if values.IsBool(con.Value) {
+		return "bool"
+	}
+	return strings.Trim(values.Placeholder(con.Value), "<>")
+}
+
+func isMultiValued(con *container.Container) bool {
+	_, ok := con.Value.(values.MultiValued)
+	return ok
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/duanqy/cli/internal/fsm"
+	"github.com/duanqy/cli/internal/values"
+)
+
+/*
+SingleDashLongOptions enables the parsing mode of the standard flag package, where long options can be given
+with a single dash, e.g. -timeout 5s or -verbose=true, in addition to the usual double dash form.
+
+A single dash arg is only read as a long option if the command has a long option with that name, e.g. with
+the -t and --timeout options, -timeout is the --timeout option and not -t with the imeout value.
+*/
+func (a *App) SingleDashLongOptions(enabled bool) {
+	a.singleDash = enabled
+}
+
+/*
+AllowAbbreviations enables the abbreviation of long options and command names, as long as the abbreviation is
+the prefix of a single option or command, e.g. --verb for --verbose. Ambiguous abbreviations are rejected with
+the list of the candidates.
+
+Abbreviations are convenient when typing but brittle in scripts, which can be broken by a new option or command.
+Scripts can opt out by setting the env var strictEnvVar to a non empty value. An empty strictEnvVar disables the
+opt-out.
+*/
+func (a *App) AllowAbbreviations(strictEnvVar string) {
+	a.abbreviations = true
+	a.strictEnvVar = strictEnvVar
+}
+
+func (c *Cmd) abbreviationsAllowed() bool {
+	root := c.root()
+	return root.abbreviations && (root.strictEnvVar == "" || os.Getenv(root.strictEnvVar) == "")
+}
+
+/*
+normalizeArgs rewrites the args of c, up to the -- operator or the first sub command name:
+single dash long options are turned into their double dash form and abbreviations are expanded, if enabled.
+The options inherited from the ancestors of c are normalized too.
+*/
+func (c *Cmd) normalizeArgs(args []string) ([]string, error) {
+	singleDash, abbrev := c.root().singleDash, c.abbreviationsAllowed()
+	if !singleDash && !abbrev {
+		return args, nil
+	}
+
+	options, idx := c.specOptions()
+	res := append([]string{}, args...)
+	expectValue := false
+	for i, arg := range args {
+		if expectValue {
+			// the value of an option may be the name of a command
+			expectValue = false
+			continue
+		}
+		if arg == "--" || c.subcommand(arg) != nil {
+			break
+		}
+		if singleDash {
+			arg = longOptionFromSingleDash(arg, idx)
+		}
+
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			if abbrev {
+				expanded, err := expandOption(arg, options, idx)
+				if err != nil {
+					return nil, err
+				}
+				arg = expanded
+			}
+			kv := strings.SplitN(arg, "=", 2)
+			con := idx[kv[0]]
+			expectValue = con != nil && len(kv) == 1 && !values.IsBool(con.Value)
+		case strings.HasPrefix(arg, "-"):
+			for j := 1; j < len(arg); j++ {
+				con := idx["-"+arg[j:j+1]]
+				if con == nil || !values.IsBool(con.Value) {
+					expectValue = con != nil && j == len(arg)-1
+					break
+				}
+			}
+		case abbrev && len(c.commands) > 0 && len(c.args) == 0:
+			sub, err := c.expandCommand(arg)
+			if err != nil {
+				return nil, err
+			}
+			if sub != "" {
+				res[i] = sub
+				return res, nil
+			}
+		}
+		res[i] = arg
+	}
+	return res, nil
+}
+
+// longOptionFromSingleDash turns a single dash long option of idx into its double dash form, e.g. -timeout into
+// --timeout
+func longOptionFromSingleDash(arg string, idx map[string]*container.Container) string {
+	if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+		return arg
+	}
+	name := strings.SplitN(arg[1:], "=", 2)[0]
+	if _, found := idx["--"+name]; found && len(name) > 1 {
+		return "-" + arg
+	}
+	return arg
+}
+
+// expandOption replaces the name of a long option by the only long option name of options it is a prefix of
+func expandOption(arg string, options []*container.Container, idx map[string]*container.Container) (string, error) {
+	kv := strings.SplitN(arg, "=", 2)
+	if _, found := idx[kv[0]]; found || len(kv[0]) < 3 {
+		return arg, nil
+	}
+
+	var (
+		candidates []string
+		seen       = map[*container.Container]bool{}
+	)
+	for _, opt := range options {
+		for _, name := range opt.Names {
+			if strings.HasPrefix(name, kv[0]) && strings.HasPrefix(name, "--") && !seen[opt] {
+				seen[opt] = true
+				candidates = append(candidates, name)
+			}
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return arg, nil
+	case 1:
+		kv[0] = candidates[0]
+		return strings.Join(kv, "="), nil
+	default:
+		return "", fmt.Errorf("ambiguous option %s, could be %s", kv[0], fsm.OrList(candidates))
+	}
+}
+
+// expandCommand returns the name of the only sub command one of whose names starts with arg, or an empty string
+func (c *Cmd) expandCommand(arg string) (string, error) {
+	var candidates []string
+	for _, sub := range c.commands {
+		if sub.hidden {
+			continue
+		}
+		for _, alias := range sub.aliases {
+			if strings.HasPrefix(alias, arg) {
+				candidates = append(candidates, alias)
+				break
+			}
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", nil
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", fmt.Errorf("ambiguous command '%s', could be %s", arg, fsm.OrList(candidates))
+	}
+}
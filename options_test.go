@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbbreviations(t *testing.T) {
+	var (
+		verbose bool
+		ran     string
+		newApp  = func() (*App, func() string) {
+			app, out := testApp(t, "app")
+			app.AllowAbbreviations("APP_STRICT")
+			app.Option("verbose", "").BoolVar(&verbose, false)
+			app.Option("version-file", "").String("")
+			app.Option("timeout", "").String("")
+			for _, name := range []string{"deploy", "delete", "status"} {
+				name := name
+				app.Command(name, "", func(cmd *Cmd) {
+					cmd.Action = func(ctx Context) error {
+						ran = name
+						return nil
+					}
+				})
+			}
+			return app, out.String
+		}
+	)
+
+	cases := []struct {
+		args    []string
+		ran     string
+		verbose bool
+		err     string
+	}{
+		{args: []string{"--verb", "st"}, ran: "status", verbose: true},
+		{args: []string{"--verbose", "depl"}, ran: "deploy", verbose: true},
+		{args: []string{"--ver", "status"}, err: "ambiguous option --ver, could be --verbose or --version-file"},
+		{args: []string{"de"}, err: "ambiguous command 'de', could be deploy or delete"},
+		{args: []string{"--time=5s", "del"}, ran: "delete"},
+	}
+	for _, cas := range cases {
+		t.Run(cas.ran+cas.err, func(t *testing.T) {
+			verbose, ran = false, ""
+			app, out := newApp()
+			err := app.Run(append([]string{"app"}, cas.args...))
+			if cas.err != "" {
+				require.Error(t, err)
+				require.Contains(t, out(), "error: "+cas.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, cas.ran, ran)
+			require.Equal(t, cas.verbose, verbose)
+		})
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		t.Setenv("APP_STRICT", "1")
+		app, out := newApp()
+		require.Error(t, app.Run([]string{"app", "--verb", "status"}))
+		require.Contains(t, out(), "error: unknown option --verb")
+
+		app, out = newApp()
+		require.Error(t, app.Run([]string{"app", "stat"}))
+		require.Contains(t, out(), "error: unknown command 'stat'")
+	})
+}
+
+func TestOptionValueNamedLikeCommand(t *testing.T) {
+	newApp := func() (*App, *string, *bool, *bool) {
+		app, _ := testApp(t, "app")
+		name := app.Option("name", "").String("")
+		verbose := app.Option("verbose", "").Bool(false)
+		ran := false
+		app.Command("run", "", func(cmd *Cmd) {
+			cmd.Action = func(ctx Context) error {
+				ran = true
+				return nil
+			}
+		})
+		return app, name, verbose, &ran
+	}
+
+	t.Run("abbreviations", func(t *testing.T) {
+		app, name, verbose, ran := newApp()
+		app.AllowAbbreviations("")
+		require.NoError(t, app.Run([]string{"app", "--name", "run", "--verb", "run"}))
+		require.Equal(t, "run", *name)
+		require.True(t, *verbose)
+		require.True(t, *ran)
+	})
+
+	t.Run("single dash", func(t *testing.T) {
+		app, name, verbose, ran := newApp()
+		app.SingleDashLongOptions(true)
+		require.NoError(t, app.Run([]string{"app", "-name", "run", "-verbose", "run"}))
+		require.Equal(t, "run", *name)
+		require.True(t, *verbose)
+		require.True(t, *ran)
+	})
+}
+
+func TestNumericShorthand(t *testing.T) {
+	app, _ := testApp(t, "app")
+	lines := app.Option("n lines", "").NumericShorthand().Int(10)
+	app.Action = func(ctx Context) error {
+		return nil
+	}
+
+	require.NoError(t, app.Run([]string{"app", "-5"}))
+	require.Equal(t, 5, *lines)
+}
+
+func TestNumericShorthandTwice(t *testing.T) {
+	app, _ := testApp(t, "app")
+	app.Option("n lines", "").NumericShorthand().Persistent().Int(10)
+	app.Command("tail", "", func(cmd *Cmd) {
+		cmd.Option("c bytes", "").NumericShorthand().Int(0)
+	})
+
+	require.EqualError(t, app.Validate().Err(), "app tail: options -c and -n both have a numeric shorthand")
+}
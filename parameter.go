@@ -12,7 +12,12 @@ import (
 
 type Parameter interface {
 	Env(key string, deprecated ...string) Parameter
+	NoEnv() Parameter
+	Delimiter(sep string) Parameter
+	EnvDelimiter(sep string) Parameter
 	Hide() Parameter
+	Persistent() Parameter
+	NumericShorthand() Parameter
 	Editor(path string) Parameter
 	Deprecated(phrases string) Parameter
 	Validate(func(string) bool) Parameter
@@ -68,7 +73,21 @@ type parameter struct {
 
 func (pa *parameter) Env(key string, deprecated ...string) Parameter {
 	pa.c.EnvVar = key
-	pa.c.ValueSetFromEnv = values.SetFromEnv(pa.c.Value, pa.c.EnvVar)
+	return pa
+}
+
+func (pa *parameter) NoEnv() Parameter {
+	pa.c.NoEnv = true
+	return pa
+}
+
+func (pa *parameter) Delimiter(sep string) Parameter {
+	pa.c.Delimiter = sep
+	return pa
+}
+
+func (pa *parameter) EnvDelimiter(sep string) Parameter {
+	pa.c.EnvDelimiter = sep
 	return pa
 }
 
@@ -98,20 +117,34 @@ func (pa *parameter) Hide() Parameter {
 	return pa
 }
 
+func (pa *parameter) Persistent() Parameter {
+	pa.c.Persistent = true
+	return pa
+}
+
+func (pa *parameter) NumericShorthand() Parameter {
+	pa.c.NumericShorthand = true
+	return pa
+}
+
 func (pa *parameter) StringSlice(def []string) *[]string {
-	panic("implement me")
+	into := new([]string)
+	pa.StringSliceVar(into, def)
+	return into
 }
 
 func (pa *parameter) StringSliceVar(p *[]string, def []string) {
-	panic("implement me")
+	pa.c.Value = values.NewStrings(p, def)
 }
 
 func (pa *parameter) IntSlice(def []int) *[]int {
-	panic("implement me")
+	into := new([]int)
+	pa.IntSliceVar(into, def)
+	return into
 }
 
 func (pa *parameter) IntSliceVar(p *[]int, def []int) {
-	panic("implement me")
+	pa.c.Value = values.NewInts(p, def)
 }
 
 func (pa *parameter) UintSlice(def []uint) *[]uint {
@@ -131,11 +164,13 @@ func (pa *parameter) Uint64SliceVar(p *[]uint64, def []uint64) {
 }
 
 func (pa *parameter) Float64Slice(def []float64) *[]float64 {
-	panic("implement me")
+	into := new([]float64)
+	pa.Float64SliceVar(into, def)
+	return into
 }
 
 func (pa *parameter) Float64SliceVar(p *[]float64, def []float64) {
-	panic("implement me")
+	pa.c.Value = values.NewFloats64(p, def)
 }
 
 func (pa *parameter) Var(v flag.Value) {
@@ -250,11 +285,16 @@ func mkOptStrs(optName string) []string {
 
 func (c *Cmd) mkOpt(opt *container.Container) {
 	opt.Names = mkOptStrs(opt.Name)
+	if len(opt.Names) == 0 {
+		c.declErrs = append(c.declErrs, fmt.Errorf("option with no name: %q", opt.Name))
+		return
+	}
 
 	c.options = append(c.options, opt)
 	for _, name := range opt.Names {
 		if _, found := c.optionsIdx[name]; found {
-			panic(fmt.Sprintf("duplicate option name %q", name))
+			c.declErrs = append(c.declErrs, fmt.Errorf("duplicate option name %q", name))
+			continue
 		}
 		c.optionsIdx[name] = opt
 	}
@@ -262,10 +302,12 @@ func (c *Cmd) mkOpt(opt *container.Container) {
 
 func (c *Cmd) mkArg(arg *container.Container) {
 	if !validArgName(arg.Name) {
-		panic(fmt.Sprintf("invalid argument name %q: must be in all caps", arg.Name))
+		c.declErrs = append(c.declErrs, fmt.Errorf("invalid argument name %q: must be in all caps", arg.Name))
+		return
 	}
 	if _, found := c.argsIdx[arg.Name]; found {
-		panic(fmt.Sprintf("duplicate argument name %q", arg.Name))
+		c.declErrs = append(c.declErrs, fmt.Errorf("duplicate argument name %q", arg.Name))
+		return
 	}
 
 	c.args = append(c.args, arg)
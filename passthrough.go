@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/duanqy/cli/internal/fsm"
+)
+
+/*
+parse matches args against the spec of c and sets the values of its options and arguments.
+
+In passthrough mode, the unknown options and the extra arguments are removed from args one by one, until the spec
+accepts what remains, and collected in c.rest in their original order. parse returns the args it last tried.
+
+Each removal parses the remaining args again, so the cost grows with the square of the number of removed args:
+this is negligible for the handful of unknown args of a command line, but not meant for thousands of them.
+*/
+func (c *Cmd) parse(args []string) ([]string, error) {
+	c.rest = nil
+	if !c.Passthrough {
+		return args, c.fsm.Parse(args)
+	}
+
+	var (
+		known   = append([]string{}, args...)
+		pos     = make([]int, len(args))
+		removed []int
+	)
+	for i := range pos {
+		pos[i] = i
+	}
+	for {
+		err := c.fsm.Parse(known)
+		uerr, ok := err.(*fsm.UsageError)
+		if !ok || !uerr.Unexpected || uerr.Pos >= len(known) {
+			sort.Ints(removed)
+			for _, i := range removed {
+				c.rest = append(c.rest, args[i])
+			}
+			return known, err
+		}
+		removed = append(removed, pos[uerr.Pos])
+		known = append(known[:uerr.Pos], known[uerr.Pos+1:]...)
+		pos = append(pos[:uerr.Pos], pos[uerr.Pos+1:]...)
+	}
+}
+
+// afterDoubleDash returns the args following the first -- operator, if any
+func afterDoubleDash(args []string) []string {
+	for i, arg := range args {
+		if arg == "--" {
+			return append([]string{}, args[i+1:]...)
+		}
+	}
+	return nil
+}
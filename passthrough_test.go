@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassthrough(t *testing.T) {
+	cases := []struct {
+		args   []string
+		ns     string
+		rest   []string
+		dashed []string
+	}{
+		{[]string{"get", "pods"}, "default", []string{"get", "pods"}, nil},
+		{[]string{"-n", "prod", "get", "--watch", "pods"}, "prod", []string{"get", "--watch", "pods"}, nil},
+		{[]string{"get", "-n", "prod", "--watch", "pods"}, "prod", []string{"get", "--watch", "pods"}, nil},
+		{[]string{"--selector", "app=web", "-n", "prod", "get"}, "prod", []string{"--selector", "app=web", "get"}, nil},
+		{[]string{"--output=json", "get"}, "default", []string{"--output=json", "get"}, nil},
+		{[]string{"get", "--", "-n", "x"}, "default", []string{"get", "-n", "x"}, []string{"-n", "x"}},
+	}
+	for _, cas := range cases {
+		t.Run(joinStrings(cas.args...), func(t *testing.T) {
+			app, _ := testApp(t, "app")
+			var (
+				rest, dashed []string
+				ns           *string
+			)
+			app.Command("exec", "", func(cmd *Cmd) {
+				cmd.Passthrough = true
+				ns = cmd.Option("n namespace", "").String("default")
+				cmd.Action = func(ctx Context) error {
+					rest, dashed = ctx.Rest(), ctx.AfterDoubleDash()
+					return nil
+				}
+			})
+
+			require.NoError(t, app.Run(append([]string{"app", "exec"}, cas.args...)))
+			require.Equal(t, cas.ns, *ns)
+			require.Equal(t, cas.rest, rest)
+			require.Equal(t, cas.dashed, dashed)
+		})
+	}
+}
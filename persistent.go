@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/duanqy/cli/internal/values"
+)
+
+// inheritedOptions returns the persistent options of the ancestors of c which are not shadowed by an option
+// of c or of a closer ancestor with the same name, indexed by name
+func (c *Cmd) inheritedOptions() ([]*container.Container, map[string]*container.Container) {
+	var (
+		res   []*container.Container
+		idx   = map[string]*container.Container{}
+		taken = map[string]bool{}
+	)
+	for name := range c.optionsIdx {
+		taken[name] = true
+	}
+	for p := c.parent; p != nil; p = p.parent {
+		for _, opt := range p.options {
+			if !opt.Persistent {
+				continue
+			}
+			added := false
+			for _, name := range opt.Names {
+				if taken[name] {
+					continue
+				}
+				idx[name] = opt
+				added = true
+			}
+			if added {
+				res = append(res, opt)
+			}
+		}
+		for name := range p.optionsIdx {
+			taken[name] = true
+		}
+	}
+	return res, idx
+}
+
+// specOptions returns the options of c merged with the inherited ones, which the [OPTIONS] shortcut accepts
+func (c *Cmd) specOptions() ([]*container.Container, map[string]*container.Container) {
+	inherited, inheritedIdx := c.inheritedOptions()
+	if len(inherited) == 0 {
+		return c.options, c.optionsIdx
+	}
+	idx := map[string]*container.Container{}
+	for name, opt := range inheritedIdx {
+		idx[name] = opt
+	}
+	for name, opt := range c.optionsIdx {
+		idx[name] = opt
+	}
+	return append(append([]*container.Container{}, c.options...), inherited...), idx
+}
+
+/*
+extractInherited removes from args the persistent options of the ancestors of c and sets their values,
+so that they can be used anywhere after the sub command name, before or after the arguments.
+It stops at the -- operator or at the first sub command name, the sub command handling the rest.
+*/
+func (c *Cmd) extractInherited(args []string) ([]string, error) {
+	rest, inherited, err := c.splitInherited(args)
+	if err != nil {
+		return nil, err
+	}
+	given := c.root().inheritedGiven
+	for _, in := range inherited {
+		if err := setInherited(in.con, in.value, !given[in.con]); err != nil {
+			return nil, fmt.Errorf("invalid value %q for option %s: %v", in.value, in.name, err)
+		}
+		given[in.con] = true
+	}
+	return rest, nil
+}
+
+// inheritedValue is a value given to a persistent option of an ancestor
+type inheritedValue struct {
+	con   *container.Container
+	name  string
+	value string
+}
+
+// splitInherited separates the persistent options of the ancestors of c from the rest of the args
+func (c *Cmd) splitInherited(args []string) ([]string, []inheritedValue, error) {
+	_, idx := c.inheritedOptions()
+	if len(idx) == 0 {
+		return args, nil, nil
+	}
+
+	var (
+		res       []string
+		inherited []inheritedValue
+	)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || c.subcommand(arg) != nil {
+			return append(res, args[i:]...), inherited, nil
+		}
+
+		name, value, hasValue := splitOption(arg)
+		con := idx[name]
+		if con == nil || c.fsm.Requires(con) || hasValue && values.IsBool(con.Value) && !strings.Contains(arg, "=") {
+			// options required by the spec and groups of short options like -vx are left to the fsm
+			res = append(res, arg)
+			continue
+		}
+		if !hasValue {
+			switch {
+			case values.IsBool(con.Value):
+				value = "true"
+			case i+1 < len(args):
+				i++
+				value = args[i]
+			default:
+				return nil, nil, fmt.Errorf("missing value for option %s", name)
+			}
+		}
+		inherited = append(inherited, inheritedValue{con: con, name: name, value: value})
+	}
+	return res, inherited, nil
+}
+
+// splitOption splits an option arg into its name and inline value, e.g. --file=x, -f=x or -fx
+func splitOption(arg string) (string, string, bool) {
+	switch {
+	case strings.HasPrefix(arg, "--"):
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) == 2 {
+			return kv[0], kv[1], true
+		}
+		return arg, "", false
+	case strings.HasPrefix(arg, "-") && len(arg) > 2:
+		return arg[:2], strings.TrimPrefix(arg[2:], "="), true
+	default:
+		return arg, "", false
+	}
+}
+
+// setInherited sets the value of a persistent option, the first value given in a run replacing the default or
+// env value of a multi valued option like fsm.fillContainers does, the next ones being appended to it
+func setInherited(con *container.Container, value string, first bool) error {
+	vs := []string{value}
+	if mv, ok := con.Value.(values.MultiValued); ok {
+		if first {
+			mv.Clear()
+		}
+		if con.Delimiter != "" {
+			var err error
+			if vs, err = values.Split(value, con.Delimiter); err != nil {
+				return err
+			}
+		}
+	}
+	for _, v := range vs {
+		if err := con.Value.Set(v); err != nil {
+			return err
+		}
+	}
+	con.ValueSetFromEnv = false
+	if con.ValueSetByUser != nil {
+		*con.ValueSetByUser = true
+	}
+	return nil
+}
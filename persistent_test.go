@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentOptions(t *testing.T) {
+	var (
+		verbose bool
+		region  string
+		target  string
+		newApp  = func(configure func(app *App)) *App {
+			app, _ := testApp(t, "app")
+			configure(app)
+			app.Option("v verbose", "").Persistent().BoolVar(&verbose, false)
+			app.Option("region", "").Persistent().StringVar(&region, "us")
+			app.Command("deploy", "", func(cmd *Cmd) {
+				cmd.Spec = "[OPTIONS] TARGET"
+				cmd.Option("f force", "").Bool(false)
+				cmd.Argument("TARGET", "").StringVar(&target, "")
+				cmd.Action = func(ctx Context) error {
+					return nil
+				}
+			})
+			return app
+		}
+	)
+
+	cases := []struct {
+		name      string
+		configure func(app *App)
+		args      []string
+	}{
+		{"before the command", func(*App) {}, []string{"-v", "--region", "eu", "deploy", "prod"}},
+		{"after the command", func(*App) {}, []string{"deploy", "--verbose", "--region=eu", "prod"}},
+		{"after the arguments", func(*App) {}, []string{"deploy", "prod", "-v", "--region", "eu"}},
+		{"single dash", func(app *App) { app.SingleDashLongOptions(true) }, []string{"deploy", "-verbose", "-region", "eu", "prod"}},
+		{"abbreviated", func(app *App) { app.AllowAbbreviations("") }, []string{"deploy", "--verb", "--reg=eu", "prod"}},
+	}
+	for _, cas := range cases {
+		t.Run(cas.name, func(t *testing.T) {
+			verbose, region, target = false, "", ""
+			app := newApp(cas.configure)
+
+			require.NoError(t, app.Run(append([]string{"app"}, cas.args...)))
+			require.True(t, verbose)
+			require.Equal(t, "eu", region)
+			require.Equal(t, "prod", target)
+		})
+	}
+}
+
+func TestPersistentSliceOption(t *testing.T) {
+	app, _ := testApp(t, "app")
+	tags := app.Option("t tag", "").Persistent().StringSlice([]string{"x"})
+	app.Command("sub", "", func(cmd *Cmd) {
+		cmd.Action = func(ctx Context) error {
+			return nil
+		}
+	})
+
+	require.NoError(t, app.Run([]string{"app", "sub", "--tag", "y"}))
+	require.Equal(t, []string{"y"}, *tags)
+
+	require.NoError(t, app.Run([]string{"app", "sub", "--tag", "y", "-t", "z"}))
+	require.Equal(t, []string{"y", "z"}, *tags)
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/duanqy/cli/internal/container"
+	"github.com/duanqy/cli/internal/fsm"
+)
+
+const defaultSuggestionDistance = 2
+
+// SuggestionDistance sets how different an unknown option or command can be from a known one, counted in
+// inserted, deleted or replaced characters, for the known one to be suggested, e.g.:
+//
+//	error: unknown option --verbos, did you mean --verbose?
+//
+// Defaults to 2. Use 0 to disable suggestions.
+func (a *App) SuggestionDistance(distance int) {
+	a.suggestionDistance = distance
+}
+
+// suggest completes the message of a usage error with the options or commands the user may have meant
+func (c *Cmd) suggest(err *fsm.UsageError) {
+	if opt, found := c.optionsIdx[err.Unknown]; found && strings.HasPrefix(err.Unknown, "-") && !opt.Hidden {
+		err.Msg = fmt.Sprintf("option %s is not accepted here", err.Unknown)
+		return
+	}
+
+	distance := c.root().suggestionDistance
+	if distance <= 0 || err.Unknown == "" {
+		return
+	}
+
+	if strings.HasPrefix(err.Unknown, "-") {
+		options, _ := c.specOptions()
+		if candidates := closest(err.Unknown, visibleOptionNames(options), distance); len(candidates) > 0 {
+			err.Msg += fmt.Sprintf(", did you mean %s?", fsm.OrList(candidates))
+			return
+		}
+		for _, other := range c.relatives() {
+			if opt, found := other.optionsIdx[err.Unknown]; found && !opt.Hidden {
+				err.Msg += fmt.Sprintf(", it is an option of '%s'", other.fullPath())
+				return
+			}
+		}
+		return
+	}
+
+	var names []string
+	for _, sub := range c.commands {
+		if sub.hidden {
+			continue
+		}
+		names = append(names, sub.aliases...)
+	}
+	if candidates := closest(err.Unknown, names, distance); len(candidates) > 0 {
+		err.Msg = fmt.Sprintf("unknown command '%s', did you mean %s?", err.Unknown, fsm.OrList(candidates))
+	}
+}
+
+// visibleOptionNames returns the names of the options which are not hidden
+func visibleOptionNames(options []*container.Container) []string {
+	var res []string
+	for _, opt := range options {
+		if !opt.Hidden {
+			res = append(res, opt.Names...)
+		}
+	}
+	return res
+}
+
+// relatives returns the sub commands and the sibling commands of c, initialized
+func (c *Cmd) relatives() []*Cmd {
+	res := append([]*Cmd{}, c.commands...)
+	if c.parent != nil {
+		for _, sibling := range c.parent.commands {
+			if sibling != c {
+				res = append(res, sibling)
+			}
+		}
+	}
+	for _, cmd := range res {
+		_ = cmd.doInit()
+	}
+	return res
+}
+
+// closest returns the names which are the nearest to s, if they are within the given edit distance and differ from s
+func closest(s string, names []string, distance int) []string {
+	var res []string
+	best := distance + 1
+	for _, name := range names {
+		d := editDistance(s, name)
+		if d == 0 || d >= len(strings.TrimLeft(name, "-")) {
+			continue
+		}
+		switch {
+		case d < best:
+			best = d
+			res = []string{name}
+		case d == best:
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// editDistance computes the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggest(t *testing.T) {
+	newApp := func() (*App, func() string) {
+		app, out := testApp(t, "app")
+		app.Option("verbose", "").Persistent().Bool(false)
+		app.Option("secret-token", "").Hide().String("")
+		app.Command("deploy", "", func(cmd *Cmd) {
+			cmd.Option("region", "").String("")
+			cmd.Action = func(ctx Context) error {
+				return nil
+			}
+		})
+		app.Command("destroy", "", nil, Hidden())
+		app.Command("run", "", func(cmd *Cmd) {
+			cmd.Spec = "[--rm | -d] IMG"
+			cmd.Option("rm", "").Bool(false)
+			cmd.Option("d", "").Bool(false)
+			cmd.Option("quiet", "").Bool(false)
+			cmd.Argument("IMG", "").String("")
+			cmd.Action = func(ctx Context) error {
+				return nil
+			}
+		})
+		return app, out.String
+	}
+
+	cases := []struct {
+		args []string
+		msg  string
+	}{
+		{[]string{"--verbos"}, "error: unknown option --verbos, did you mean --verbose?"},
+		{[]string{"--secret-tokn"}, "error: unknown option --secret-tokn\n"},
+		{[]string{"deploy", "--regoin", "eu"}, "error: unknown option --regoin, did you mean --region?"},
+		{[]string{"deploy", "--verbos"}, "error: unknown option --verbos, did you mean --verbose?"},
+		{[]string{"--region", "eu"}, "error: unknown option --region, it is an option of 'app deploy'"},
+		{[]string{"deplyo"}, "error: unknown command 'deplyo', did you mean deploy?"},
+		{[]string{"destroi"}, "error: unexpected argument 'destroi'\n"},
+		{[]string{"run", "--quiet", "img"}, "error: option --quiet is not accepted here\n"},
+		{[]string{"run", "--rm", "-d", "img"}, "error: -d cannot be combined with --rm\n"},
+	}
+	for _, cas := range cases {
+		t.Run(cas.msg, func(t *testing.T) {
+			app, out := newApp()
+			require.Error(t, app.Run(append([]string{"app"}, cas.args...)))
+			require.Contains(t, out(), cas.msg)
+		})
+	}
+}
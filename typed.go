@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"github.com/duanqy/cli/internal/values"
+)
+
+// Typed declares param as holding a value of type T and returns a pointer which will be populated when the app is run.
+//
+// parse converts the user input (command line or env var) into a T, format renders a T in help messages.
+// format may be nil, in which case fmt.Sprint is used. def is the value used when the user does not provide one.
+//
+//	endpoint := cli.Typed(app.Option("u url", "Endpoint"), url.Parse, (*url.URL).String, nil)
+func Typed[T any](param Parameter, parse func(string) (T, error), format func(T) string, def T) *T {
+	into := new(T)
+	TypedVar(param, into, parse, format, def)
+	return into
+}
+
+// TypedVar is like Typed, but populates an existing variable instead of declaring a new one
+func TypedVar[T any](param Parameter, p *T, parse func(string) (T, error), format func(T) string, def T) {
+	param.Var(values.NewTyped(p, parse, format, def))
+}
+
+// TypedSlice declares param as holding multiple values of type T and returns a pointer which will be populated
+// when the app is run.
+//
+// Like the other slice parameters, the option can be repeated to accumulate values, and an env var value
+// is split on commas.
+func TypedSlice[T any](param Parameter, parse func(string) (T, error), format func(T) string, def []T) *[]T {
+	into := new([]T)
+	TypedSliceVar(param, into, parse, format, def)
+	return into
+}
+
+// TypedSliceVar is like TypedSlice, but populates an existing variable instead of declaring a new one
+func TypedSliceVar[T any](param Parameter, p *[]T, parse func(string) (T, error), format func(T) string, def []T) {
+	param.Var(values.NewTypedSlice(p, parse, format, def))
+}